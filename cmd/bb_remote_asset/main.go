@@ -2,20 +2,26 @@ package main
 
 import (
 	"context"
+	"net/url"
 	"os"
 	"time"
 
 	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/pkg/admin"
 	"github.com/buildbarn/bb-remote-asset/pkg/configuration"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	admin_pb "github.com/buildbarn/bb-remote-asset/pkg/proto/admin"
 	"github.com/buildbarn/bb-remote-asset/pkg/proto/configuration/bb_remote_asset"
 	"github.com/buildbarn/bb-remote-asset/pkg/push"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-remote-asset/pkg/webui"
 	"github.com/buildbarn/bb-storage/pkg/auth"
 	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/global"
 	bb_grpc "github.com/buildbarn/bb-storage/pkg/grpc"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
 	"github.com/buildbarn/bb-storage/pkg/program"
 	"github.com/buildbarn/bb-storage/pkg/util"
 	protostatus "google.golang.org/genproto/googleapis/rpc/status"
@@ -58,6 +64,16 @@ func main() {
 			return util.StatusWrap(err, "Failed to create Push Authorizer from Configuration")
 		}
 
+		deleteAuthorizer, err := auth.DefaultAuthorizerFactory.NewAuthorizerFromConfiguration(config.DeleteAuthorizer)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create Delete Authorizer from Configuration")
+		}
+
+		adminAuthorizer, err := auth.DefaultAuthorizerFactory.NewAuthorizerFromConfiguration(config.AdminAuthorizer)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create Admin Authorizer from Configuration")
+		}
+
 		// Initialize CAS storage access
 		contentAddressableStorageInfo, err := blobstore_configuration.NewBlobAccessFromConfiguration(
 			dependenciesGroup,
@@ -70,20 +86,64 @@ func main() {
 		if err != nil {
 			return util.StatusWrap(err, "Failed to create CAS blob access")
 		}
+		freshnessPolicy, err := configuration.NewFreshnessPolicyFromConfiguration(config.AssetFreshness)
+		if err != nil {
+			return util.StatusWrap(err, "Failed to create asset freshness policy")
+		}
+
 		var assetStore storage.AssetStore
+		var enumerableAssetStore storage.EnumerableAssetStore
+		var referenceIndex storage.ReferenceIndex
 		if config.AssetCache != nil {
-			assetStore, err = configuration.NewAssetStoreFromConfiguration(
+			if referenceIndexConfiguration := config.AssetCache.ReferenceIndex; referenceIndexConfiguration != nil {
+				referenceIndex, err = configuration.NewReferenceIndexFromConfiguration(
+					referenceIndexConfiguration,
+					grpcClientFactory,
+					int(config.MaximumMessageSizeBytes),
+					dependenciesGroup,
+				)
+				if err != nil {
+					return util.StatusWrap(err, "Failed to create reference index")
+				}
+			}
+			assetStoreInfo, err := configuration.NewAssetStoreFromConfiguration(
 				config.AssetCache,
 				&contentAddressableStorageInfo,
 				grpcClientFactory,
 				int(config.MaximumMessageSizeBytes),
 				dependenciesGroup,
+				freshnessPolicy,
 				fetchAuthorizer,
 				pushAuthorizer,
+				deleteAuthorizer,
+				referenceIndex,
 			)
 			if err != nil {
 				return util.StatusWrap(err, "Failed to create asset store")
 			}
+			assetStore = assetStoreInfo.AssetStore
+			enumerableAssetStore = assetStoreInfo.EnumerableAssetStore
+
+			if sweeperConfiguration := config.AssetCache.ExpiredAssetSweeper; sweeperConfiguration != nil {
+				if enumerableAssetStore == nil {
+					return status.Error(codes.InvalidArgument, "Expired asset sweeping requires an asset cache backend that supports enumeration")
+				}
+				if err := sweeperConfiguration.Interval.CheckValid(); err != nil {
+					return util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid expired asset sweeper interval")
+				}
+				interval := sweeperConfiguration.Interval.AsDuration()
+				if interval <= 0 {
+					return status.Error(codes.InvalidArgument, "Expired asset sweeper interval must be positive")
+				}
+				dependenciesGroup.Go(
+					storage.NewExpiredAssetSweeper(
+						enumerableAssetStore,
+						freshnessPolicy,
+						clock.SystemClock,
+						interval,
+						sweeperConfiguration.GracePeriod.AsDuration(),
+					).Run)
+			}
 		}
 
 		allowUpdatesForInstances := map[bb_digest.InstanceName]bool{}
@@ -98,6 +158,7 @@ func main() {
 		fetchServer, err := configuration.NewFetcherFromConfiguration(
 			config.Fetcher,
 			assetStore,
+			freshnessPolicy,
 			contentAddressableStorageInfo.BlobAccess,
 			grpcClientFactory,
 			int(config.MaximumMessageSizeBytes),
@@ -107,11 +168,22 @@ func main() {
 			return util.StatusWrap(err, "Failed to initialize fetch server from configuration")
 		}
 
+		// Keep track of recent fetches for display by the web UI.
+		var fetchHistory *fetch.FetchHistory
+		if webUIConfiguration := config.WebUi; webUIConfiguration != nil {
+			if webUIConfiguration.FetchHistorySize < 0 {
+				return status.Error(codes.InvalidArgument, "Web UI fetch history size cannot be negative")
+			}
+			fetchHistory = fetch.NewFetchHistory(int(webUIConfiguration.FetchHistorySize))
+			fetchServer = fetch.NewHistoryRecordingFetcher(fetchServer, fetchHistory, clock.SystemClock)
+		}
+
 		var metricsPushServer remoteasset.PushServer
 		if assetStore != nil {
 			pushServer := push.NewAssetPushServer(
 				assetStore,
-				allowUpdatesForInstances)
+				allowUpdatesForInstances,
+				freshnessPolicy)
 			metricsPushServer = push.NewMetricsAssetPushServer(pushServer, clock.SystemClock, "push")
 		} else {
 			metricsPushServer = push.NewErrorPushServer(&protostatus.Status{
@@ -120,6 +192,23 @@ func main() {
 			})
 		}
 
+		// Spawn HTTP servers for the web UI.
+		if webUIConfiguration := config.WebUi; webUIConfiguration != nil {
+			var browserURL *url.URL
+			if webUIConfiguration.BrowserUrl != "" {
+				browserURL, err = url.Parse(webUIConfiguration.BrowserUrl)
+				if err != nil {
+					return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to parse browser URL")
+				}
+			}
+			bb_http.NewServersFromConfigurationAndServe(
+				webUIConfiguration.HttpServers,
+				bb_http.NewMetricsHandler(
+					webui.NewAssetBrowserHandler(assetStore, fetchHistory, fetchAuthorizer, browserURL),
+					"AssetBrowser"),
+				siblingsGroup)
+		}
+
 		// Spawn gRPC servers for client and worker traffic.
 		if err := bb_grpc.NewServersFromConfigurationAndServe(
 			config.GrpcServers,
@@ -127,6 +216,13 @@ func main() {
 				// Register services
 				remoteasset.RegisterFetchServer(s, fetchServer)
 				remoteasset.RegisterPushServer(s, metricsPushServer)
+				if assetStore != nil {
+					admin_pb.RegisterAssetAdminServer(
+						s,
+						admin.NewAuthorizingAssetAdminServer(
+							admin.NewAssetAdminServer(assetStore, enumerableAssetStore, referenceIndex, fetchServer, clock.SystemClock),
+							adminAuthorizer))
+				}
 			},
 			siblingsGroup,
 		); err != nil {
@@ -0,0 +1,293 @@
+package admin
+
+import (
+	"context"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	admin_pb "github.com/buildbarn/bb-remote-asset/pkg/proto/admin"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type assetAdminServer struct {
+	assetStore           storage.AssetStore
+	enumerableAssetStore storage.EnumerableAssetStore
+	referenceIndex       storage.ReferenceIndex
+	fetcher              remoteasset.FetchServer
+	clock                clock.Clock
+}
+
+// NewAssetAdminServer creates a gRPC service that permits operators to
+// inspect and manage the contents of an asset cache. Forced refetches
+// are performed through the provided Fetch service, which is expected
+// to store the results in the same asset cache.
+//
+// The enumerable asset store and the reference index may be nil, in
+// which case assets cannot be listed and looked up by digest,
+// respectively.
+func NewAssetAdminServer(assetStore storage.AssetStore, enumerableAssetStore storage.EnumerableAssetStore, referenceIndex storage.ReferenceIndex, fetcher remoteasset.FetchServer, clock clock.Clock) admin_pb.AssetAdminServer {
+	return &assetAdminServer{
+		assetStore:           assetStore,
+		enumerableAssetStore: enumerableAssetStore,
+		referenceIndex:       referenceIndex,
+		fetcher:              fetcher,
+		clock:                clock,
+	}
+}
+
+func (s *assetAdminServer) GetAsset(ctx context.Context, req *admin_pb.GetAssetRequest) (*admin_pb.GetAssetResponse, error) {
+	if len(req.Uris) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "GetAsset requires at least one URI")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	assetData, err := s.assetStore.Get(ctx, storage.NewAssetReference(req.Uris, req.Qualifiers), instanceName)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to obtain asset")
+	}
+	return &admin_pb.GetAssetResponse{
+		Asset: assetData,
+	}, nil
+}
+
+func (s *assetAdminServer) InvalidateAsset(ctx context.Context, req *admin_pb.InvalidateAssetRequest) (*admin_pb.InvalidateAssetResponse, error) {
+	if len(req.Uris) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "InvalidateAsset requires at least one URI")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	// Fetch and Push store assets both under the full list of URIs
+	// and under every URI individually. Remove all of them.
+	assetRef := storage.NewAssetReference(req.Uris, req.Qualifiers)
+	if err := s.assetStore.Delete(ctx, assetRef, instanceName); err != nil {
+		return nil, util.StatusWrap(err, "Failed to invalidate asset")
+	}
+	if len(req.Uris) > 1 {
+		for _, uri := range req.Uris {
+			assetRef := storage.NewAssetReference([]string{uri}, req.Qualifiers)
+			if err := s.assetStore.Delete(ctx, assetRef, instanceName); err != nil {
+				return nil, util.StatusWrapf(err, "Failed to invalidate asset for URI %#v", uri)
+			}
+		}
+	}
+	return &admin_pb.InvalidateAssetResponse{}, nil
+}
+
+func (s *assetAdminServer) PinAsset(ctx context.Context, req *admin_pb.PinAssetRequest) (*admin_pb.PinAssetResponse, error) {
+	if len(req.Uris) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "PinAsset requires at least one URI")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	// If no digest is provided, pin the asset that is currently
+	// stored in the asset cache. Only its expiration time is
+	// removed, as assets without an expiration time are returned
+	// indefinitely.
+	assetRef := storage.NewAssetReference(req.Uris, req.Qualifiers)
+	var assetData *asset.Asset
+	if req.Digest == nil {
+		assetData, err = s.assetStore.Get(ctx, assetRef, instanceName)
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to obtain asset to pin")
+		}
+		assetData.ExpireAt = nil
+	} else {
+		digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(req.Digest.Hash))
+		if err != nil {
+			return nil, util.StatusWrap(err, "Invalid digest")
+		}
+		if _, err := digestFunction.NewDigestFromProto(req.Digest); err != nil {
+			return nil, util.StatusWrap(err, "Invalid digest")
+		}
+		assetData = &asset.Asset{
+			Digest:      req.Digest,
+			LastUpdated: timestamppb.New(s.clock.Now()),
+		}
+	}
+	if err := s.assetStore.Put(ctx, assetRef, assetData, instanceName); err != nil {
+		return nil, util.StatusWrap(err, "Failed to pin asset")
+	}
+	if len(req.Uris) > 1 {
+		for _, uri := range req.Uris {
+			assetRef := storage.NewAssetReference([]string{uri}, req.Qualifiers)
+			if err := s.assetStore.Put(ctx, assetRef, assetData, instanceName); err != nil {
+				return nil, util.StatusWrapf(err, "Failed to pin asset for URI %#v", uri)
+			}
+		}
+	}
+	return &admin_pb.PinAssetResponse{
+		Asset: assetData,
+	}, nil
+}
+
+func (s *assetAdminServer) RefetchAsset(ctx context.Context, req *admin_pb.RefetchAssetRequest) (*admin_pb.RefetchAssetResponse, error) {
+	if len(req.Uris) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "RefetchAsset requires at least one URI")
+	}
+
+	// Setting oldest_content_accepted to the current time causes
+	// CachingFetcher to ignore any copy present in the asset cache,
+	// while still storing the result of the fetch.
+	oldestContentAccepted := timestamppb.New(s.clock.Now())
+	if req.Directory {
+		response, err := s.fetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName:          req.InstanceName,
+			Timeout:               req.Timeout,
+			OldestContentAccepted: oldestContentAccepted,
+			Uris:                  req.Uris,
+			Qualifiers:            req.Qualifiers,
+		})
+		if err != nil {
+			return nil, util.StatusWrap(err, "Failed to refetch directory")
+		}
+		if response.Status.GetCode() != int32(codes.OK) {
+			return nil, util.StatusWrap(status.ErrorProto(response.Status), "Failed to refetch directory")
+		}
+		return &admin_pb.RefetchAssetResponse{
+			Uri:    response.Uri,
+			Digest: response.RootDirectoryDigest,
+		}, nil
+	}
+
+	response, err := s.fetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+		InstanceName:          req.InstanceName,
+		Timeout:               req.Timeout,
+		OldestContentAccepted: oldestContentAccepted,
+		Uris:                  req.Uris,
+		Qualifiers:            req.Qualifiers,
+	})
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to refetch blob")
+	}
+	if response.Status.GetCode() != int32(codes.OK) {
+		return nil, util.StatusWrap(status.ErrorProto(response.Status), "Failed to refetch blob")
+	}
+	return &admin_pb.RefetchAssetResponse{
+		Uri:    response.Uri,
+		Digest: response.BlobDigest,
+	}, nil
+}
+
+// redactAssetReference returns a copy of an asset reference that can
+// be returned to administrators, as the values of qualifiers that may
+// contain credentials belong to the clients that provided them.
+func redactAssetReference(ref *asset.AssetReference) *asset.AssetReference {
+	return &asset.AssetReference{
+		Uris:       ref.Uris,
+		Qualifiers: qualifier.Redact(ref.Qualifiers),
+	}
+}
+
+func (s *assetAdminServer) GetAssetReferences(ctx context.Context, req *admin_pb.GetAssetReferencesRequest) (*admin_pb.GetAssetReferencesResponse, error) {
+	if s.referenceIndex == nil {
+		return nil, status.Error(codes.FailedPrecondition, "The asset cache is not configured to maintain a reference index")
+	}
+	if req.Digest == nil {
+		return nil, status.Error(codes.InvalidArgument, "GetAssetReferences requires a digest")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	assetRefs, err := s.referenceIndex.Get(ctx, req.Digest, instanceName)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to obtain asset references")
+	}
+
+	// The index is append-only, meaning that assets may have been
+	// deleted or overwritten since they were added. Report which
+	// of them still refer to the digest.
+	references := make([]*admin_pb.GetAssetReferencesResponse_Reference, 0, len(assetRefs))
+	for _, assetRef := range assetRefs {
+		current := false
+		assetData, err := s.assetStore.Get(ctx, assetRef, instanceName)
+		if err == nil {
+			current = proto.Equal(assetData.Digest, req.Digest)
+		} else if status.Code(err) != codes.NotFound {
+			return nil, util.StatusWrap(err, "Failed to obtain asset")
+		}
+		references = append(references, &admin_pb.GetAssetReferencesResponse_Reference{
+			Reference: redactAssetReference(assetRef),
+			Current:   current,
+		})
+	}
+	return &admin_pb.GetAssetReferencesResponse{
+		References: references,
+	}, nil
+}
+
+func (s *assetAdminServer) ListAssets(ctx context.Context, req *admin_pb.ListAssetsRequest) (*admin_pb.ListAssetsResponse, error) {
+	if s.enumerableAssetStore == nil {
+		return nil, status.Error(codes.FailedPrecondition, "The asset cache backend does not support listing assets")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	enumeratedAssets, nextPageToken, err := s.enumerableAssetStore.List(ctx, instanceName, req.UriPrefix, req.PageToken, int(req.PageSize))
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to list assets")
+	}
+	entries := make([]*admin_pb.ListAssetsResponse_Entry, 0, len(enumeratedAssets))
+	for _, enumeratedAsset := range enumeratedAssets {
+		entries = append(entries, &admin_pb.ListAssetsResponse_Entry{
+			Reference: redactAssetReference(enumeratedAsset.Reference),
+			Asset:     enumeratedAsset.Asset,
+		})
+	}
+	return &admin_pb.ListAssetsResponse{
+		Assets:        entries,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+func (s *assetAdminServer) GetAssetStatistics(ctx context.Context, req *admin_pb.GetAssetStatisticsRequest) (*admin_pb.GetAssetStatisticsResponse, error) {
+	if s.enumerableAssetStore == nil {
+		return nil, status.Error(codes.FailedPrecondition, "The asset cache backend does not support obtaining statistics")
+	}
+
+	instanceName, err := digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	statistics, err := s.enumerableAssetStore.GetStatistics(ctx, instanceName)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to obtain statistics")
+	}
+	response := &admin_pb.GetAssetStatisticsResponse{
+		Assets:          statistics.Assets,
+		ExpiredAssets:   statistics.ExpiredAssets,
+		DistinctDigests: statistics.DistinctDigests,
+	}
+	if !statistics.OldestLastUpdated.IsZero() {
+		response.OldestLastUpdated = timestamppb.New(statistics.OldestLastUpdated)
+		response.NewestLastUpdated = timestamppb.New(statistics.NewestLastUpdated)
+	}
+	return response, nil
+}
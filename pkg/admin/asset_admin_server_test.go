@@ -0,0 +1,420 @@
+package admin_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/admin"
+	admin_pb "github.com/buildbarn/bb-remote-asset/pkg/proto/admin"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAssetAdminServerGetAsset(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	uri := "https://example.com/example.tar.gz"
+	assetRef := storage.NewAssetReference([]string{uri}, nil)
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, nil, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+	t.Run("Found", func(t *testing.T) {
+		assetData := &asset.Asset{
+			Digest:      blobDigest,
+			LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+		}
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(assetData, nil)
+
+		response, err := adminServer.GetAsset(ctx, &admin_pb.GetAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.GetAssetResponse{Asset: assetData}, response)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Object not found"))
+
+		_, err := adminServer.GetAsset(ctx, &admin_pb.GetAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to obtain asset: Object not found"), err)
+	})
+}
+
+func TestAssetAdminServerInvalidateAsset(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	uri1 := "https://example.com/example1.tar.gz"
+	uri2 := "https://example.com/example2.tar.gz"
+	qualifiers := []*remoteasset.Qualifier{{Name: "resource_type", Value: "application/x-tar"}}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, nil, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+	t.Run("NoURIs", func(t *testing.T) {
+		_, err := adminServer.InvalidateAsset(ctx, &admin_pb.InvalidateAssetRequest{
+			InstanceName: "instance",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "InvalidateAsset requires at least one URI"), err)
+	})
+
+	t.Run("SingleURI", func(t *testing.T) {
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri1}, qualifiers), instanceName)
+
+		_, err := adminServer.InvalidateAsset(ctx, &admin_pb.InvalidateAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1},
+			Qualifiers:   qualifiers,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("MultipleURIs", func(t *testing.T) {
+		// Assets should be removed both for the full list of URIs,
+		// and for every URI individually.
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri1, uri2}, qualifiers), instanceName)
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri1}, qualifiers), instanceName)
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri2}, qualifiers), instanceName)
+
+		_, err := adminServer.InvalidateAsset(ctx, &admin_pb.InvalidateAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1, uri2},
+			Qualifiers:   qualifiers,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("StorageFailure", func(t *testing.T) {
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri1}, qualifiers), instanceName).
+			Return(status.Error(codes.PermissionDenied, "Not authorized"))
+
+		_, err := adminServer.InvalidateAsset(ctx, &admin_pb.InvalidateAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1},
+			Qualifiers:   qualifiers,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "Failed to invalidate asset: Not authorized"), err)
+	})
+}
+
+func TestAssetAdminServerPinAsset(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	uri1 := "https://example.com/example1.tar.gz"
+	uri2 := "https://example.com/example2.tar.gz"
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	pinnedAsset := &asset.Asset{
+		Digest:      blobDigest,
+		LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+	}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, nil, nil, mock.NewMockFetcher(ctrl), clock)
+
+	t.Run("ExplicitDigest", func(t *testing.T) {
+		// Assets should be stored without an expiration time, both
+		// for the full list of URIs and for every URI individually.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetStore.EXPECT().Put(ctx, storage.NewAssetReference([]string{uri1, uri2}, nil), testutil.EqProto(t, pinnedAsset), instanceName)
+		assetStore.EXPECT().Put(ctx, storage.NewAssetReference([]string{uri1}, nil), testutil.EqProto(t, pinnedAsset), instanceName)
+		assetStore.EXPECT().Put(ctx, storage.NewAssetReference([]string{uri2}, nil), testutil.EqProto(t, pinnedAsset), instanceName)
+
+		response, err := adminServer.PinAsset(ctx, &admin_pb.PinAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1, uri2},
+			Digest:       blobDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.PinAssetResponse{Asset: pinnedAsset}, response)
+	})
+
+	t.Run("InvalidDigest", func(t *testing.T) {
+		_, err := adminServer.PinAsset(ctx, &admin_pb.PinAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1},
+			Digest:       &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: -1},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Invalid digest: Invalid digest size: -1 bytes"), err)
+
+		_, err = adminServer.PinAsset(ctx, &admin_pb.PinAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1},
+			Digest:       &remoteexecution.Digest{Hash: "d0d829c4", SizeBytes: 123},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("CurrentDigest", func(t *testing.T) {
+		// Without a digest, the asset that is currently stored
+		// should have its expiration time removed, while its
+		// other properties are retained.
+		assetRef := storage.NewAssetReference([]string{uri1}, nil)
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(&asset.Asset{
+			Digest:      blobDigest,
+			ExpireAt:    timestamppb.New(time.Unix(2000, 0)),
+			LastUpdated: timestamppb.New(time.Unix(500, 0)),
+			OriginUri:   uri1,
+		}, nil)
+		currentAsset := &asset.Asset{
+			Digest:      blobDigest,
+			LastUpdated: timestamppb.New(time.Unix(500, 0)),
+			OriginUri:   uri1,
+		}
+		assetStore.EXPECT().Put(ctx, assetRef, testutil.EqProto(t, currentAsset), instanceName)
+
+		response, err := adminServer.PinAsset(ctx, &admin_pb.PinAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri1},
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.PinAssetResponse{Asset: currentAsset}, response)
+	})
+}
+
+func TestAssetAdminServerRefetchAsset(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/example.tar.gz"
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+
+	fetcher := mock.NewMockFetcher(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	adminServer := admin.NewAssetAdminServer(mock.NewMockAssetStore(ctrl), nil, nil, fetcher, clock)
+
+	t.Run("Blob", func(t *testing.T) {
+		// Cached copies should be ignored by requiring content to
+		// be newer than the current time.
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		fetcher.EXPECT().FetchBlob(ctx, testutil.EqProto(t, &remoteasset.FetchBlobRequest{
+			InstanceName:          "instance",
+			Timeout:               durationpb.New(time.Minute),
+			OldestContentAccepted: timestamppb.New(time.Unix(1000, 0)),
+			Uris:                  []string{uri},
+		})).Return(&remoteasset.FetchBlobResponse{
+			Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+			Uri:        uri,
+			BlobDigest: blobDigest,
+		}, nil)
+
+		response, err := adminServer.RefetchAsset(ctx, &admin_pb.RefetchAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+			Timeout:      durationpb.New(time.Minute),
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.RefetchAssetResponse{
+			Uri:    uri,
+			Digest: blobDigest,
+		}, response)
+	})
+
+	t.Run("DirectoryFailure", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		fetcher.EXPECT().FetchDirectory(ctx, testutil.EqProto(t, &remoteasset.FetchDirectoryRequest{
+			InstanceName:          "instance",
+			OldestContentAccepted: timestamppb.New(time.Unix(1000, 0)),
+			Uris:                  []string{uri},
+		})).Return(&remoteasset.FetchDirectoryResponse{
+			Status: status.New(codes.NotFound, "Server returned 404 Not Found").Proto(),
+		}, nil)
+
+		_, err := adminServer.RefetchAsset(ctx, &admin_pb.RefetchAssetRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+			Directory:    true,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Failed to refetch directory: Server returned 404 Not Found"), err)
+	})
+}
+
+func TestAssetAdminServerGetAssetReferences(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	otherBlobDigest := &remoteexecution.Digest{Hash: "2ca7bbbba4d3a8b5a0a7ffc7e0c2d7b5a71a1d2b5b8d3b6e9f3f95d0b7a3c0e4", SizeBytes: 456}
+	currentRef := storage.NewAssetReference([]string{"https://example.com/current.tar.gz"}, nil)
+	overwrittenRef := storage.NewAssetReference([]string{"https://example.com/overwritten.tar.gz"}, nil)
+	deletedRef := storage.NewAssetReference([]string{"https://example.com/deleted.tar.gz"}, nil)
+	secretRef := storage.NewAssetReference(
+		[]string{"https://example.com/secret.tar.gz"},
+		[]*remoteasset.Qualifier{{Name: "bazel.auth_headers", Value: `{"https://example.com/secret.tar.gz":{"Authorization":["Bearer hunter2"]}}`}})
+	redactedSecretRef := storage.NewAssetReference(
+		[]string{"https://example.com/secret.tar.gz"},
+		[]*remoteasset.Qualifier{{Name: "bazel.auth_headers", Value: "[redacted]"}})
+
+	t.Run("NoReferenceIndex", func(t *testing.T) {
+		adminServer := admin.NewAssetAdminServer(mock.NewMockAssetStore(ctrl), nil, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+		_, err := adminServer.GetAssetReferences(ctx, &admin_pb.GetAssetReferencesRequest{
+			InstanceName: "instance",
+			Digest:       blobDigest,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "The asset cache is not configured to maintain a reference index"), err)
+	})
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	referenceIndex := mock.NewMockReferenceIndex(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, nil, referenceIndex, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+	t.Run("Success", func(t *testing.T) {
+		referenceIndex.EXPECT().Get(ctx, testutil.EqProto(t, blobDigest), instanceName).
+			Return([]*asset.AssetReference{currentRef, overwrittenRef, deletedRef, secretRef}, nil)
+		assetStore.EXPECT().Get(ctx, currentRef, instanceName).Return(&asset.Asset{Digest: blobDigest}, nil)
+		assetStore.EXPECT().Get(ctx, overwrittenRef, instanceName).Return(&asset.Asset{Digest: otherBlobDigest}, nil)
+		assetStore.EXPECT().Get(ctx, deletedRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset has been deleted"))
+		assetStore.EXPECT().Get(ctx, secretRef, instanceName).Return(&asset.Asset{Digest: blobDigest}, nil)
+
+		response, err := adminServer.GetAssetReferences(ctx, &admin_pb.GetAssetReferencesRequest{
+			InstanceName: "instance",
+			Digest:       blobDigest,
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.GetAssetReferencesResponse{
+			References: []*admin_pb.GetAssetReferencesResponse_Reference{
+				{Reference: currentRef, Current: true},
+				{Reference: overwrittenRef, Current: false},
+				{Reference: deletedRef, Current: false},
+				// Credentials should not be returned.
+				{Reference: redactedSecretRef, Current: true},
+			},
+		}, response)
+	})
+
+	t.Run("IndexFailure", func(t *testing.T) {
+		referenceIndex.EXPECT().Get(ctx, testutil.EqProto(t, blobDigest), instanceName).
+			Return(nil, status.Error(codes.Unavailable, "Server offline"))
+
+		_, err := adminServer.GetAssetReferences(ctx, &admin_pb.GetAssetReferencesRequest{
+			InstanceName: "instance",
+			Digest:       blobDigest,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Failed to obtain asset references: Server offline"), err)
+	})
+}
+
+func TestAssetAdminServerListAssets(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.tar.gz"}, nil)
+	assetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123},
+	}
+
+	t.Run("NotEnumerable", func(t *testing.T) {
+		adminServer := admin.NewAssetAdminServer(mock.NewMockAssetStore(ctrl), nil, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+		_, err := adminServer.ListAssets(ctx, &admin_pb.ListAssetsRequest{
+			InstanceName: "instance",
+			PageSize:     10,
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.FailedPrecondition, "The asset cache backend does not support listing assets"), err)
+	})
+
+	assetStore := mock.NewMockEnumerableAssetStore(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, assetStore, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+	t.Run("Success", func(t *testing.T) {
+		secretRef := storage.NewAssetReference(
+			[]string{"https://example.com/secret.tar.gz"},
+			[]*remoteasset.Qualifier{{Name: "auth.basic.password", Value: "hunter2"}})
+		assetStore.EXPECT().List(ctx, instanceName, "https://example.com/", "42", 10).Return([]storage.EnumeratedAsset{
+			{Reference: assetRef, Asset: assetData},
+			{Reference: secretRef, Asset: assetData},
+		}, "43", nil)
+
+		response, err := adminServer.ListAssets(ctx, &admin_pb.ListAssetsRequest{
+			InstanceName: "instance",
+			UriPrefix:    "https://example.com/",
+			PageSize:     10,
+			PageToken:    "42",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.ListAssetsResponse{
+			Assets: []*admin_pb.ListAssetsResponse_Entry{
+				{Reference: assetRef, Asset: assetData},
+				// Credentials should not be returned.
+				{
+					Reference: storage.NewAssetReference(
+						[]string{"https://example.com/secret.tar.gz"},
+						[]*remoteasset.Qualifier{{Name: "auth.basic.password", Value: "[redacted]"}}),
+					Asset: assetData,
+				},
+			},
+			NextPageToken: "43",
+		}, response)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		assetStore.EXPECT().List(ctx, instanceName, "", "", 0).
+			Return(nil, "", status.Error(codes.InvalidArgument, "Page size must be positive"))
+
+		_, err := adminServer.ListAssets(ctx, &admin_pb.ListAssetsRequest{
+			InstanceName: "instance",
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Failed to list assets: Page size must be positive"), err)
+	})
+}
+
+func TestAssetAdminServerGetAssetStatistics(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	assetStore := mock.NewMockEnumerableAssetStore(ctrl)
+	adminServer := admin.NewAssetAdminServer(assetStore, assetStore, nil, mock.NewMockFetcher(ctrl), clock.SystemClock)
+
+	t.Run("Empty", func(t *testing.T) {
+		assetStore.EXPECT().GetStatistics(ctx, instanceName).Return(&storage.AssetStoreStatistics{}, nil)
+
+		response, err := adminServer.GetAssetStatistics(ctx, &admin_pb.GetAssetStatisticsRequest{
+			InstanceName: "instance",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.GetAssetStatisticsResponse{}, response)
+	})
+
+	t.Run("NonEmpty", func(t *testing.T) {
+		assetStore.EXPECT().GetStatistics(ctx, instanceName).Return(&storage.AssetStoreStatistics{
+			Assets:            3,
+			ExpiredAssets:     1,
+			DistinctDigests:   2,
+			OldestLastUpdated: time.Unix(500, 0),
+			NewestLastUpdated: time.Unix(700, 0),
+		}, nil)
+
+		response, err := adminServer.GetAssetStatistics(ctx, &admin_pb.GetAssetStatisticsRequest{
+			InstanceName: "instance",
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &admin_pb.GetAssetStatisticsResponse{
+			Assets:            3,
+			ExpiredAssets:     1,
+			DistinctDigests:   2,
+			OldestLastUpdated: timestamppb.New(time.Unix(500, 0)),
+			NewestLastUpdated: timestamppb.New(time.Unix(700, 0)),
+		}, response)
+	})
+}
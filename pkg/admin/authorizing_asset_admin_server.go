@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+
+	admin_pb "github.com/buildbarn/bb-remote-asset/pkg/proto/admin"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+)
+
+type authorizingAssetAdminServer struct {
+	server     admin_pb.AssetAdminServer
+	authorizer auth.Authorizer
+}
+
+// NewAuthorizingAssetAdminServer creates a decorator for
+// AssetAdminServer that validates all requests against an Authorizer.
+func NewAuthorizingAssetAdminServer(server admin_pb.AssetAdminServer, authorizer auth.Authorizer) admin_pb.AssetAdminServer {
+	return &authorizingAssetAdminServer{
+		server:     server,
+		authorizer: authorizer,
+	}
+}
+
+func (s *authorizingAssetAdminServer) authorize(ctx context.Context, instanceNameStr string) error {
+	instanceName, err := digest.NewInstanceName(instanceNameStr)
+	if err != nil {
+		return util.StatusWrapf(err, "Invalid instance name %#v", instanceNameStr)
+	}
+	return auth.AuthorizeSingleInstanceName(ctx, s.authorizer, instanceName)
+}
+
+func (s *authorizingAssetAdminServer) GetAsset(ctx context.Context, req *admin_pb.GetAssetRequest) (*admin_pb.GetAssetResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.GetAsset(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) InvalidateAsset(ctx context.Context, req *admin_pb.InvalidateAssetRequest) (*admin_pb.InvalidateAssetResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.InvalidateAsset(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) PinAsset(ctx context.Context, req *admin_pb.PinAssetRequest) (*admin_pb.PinAssetResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.PinAsset(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) RefetchAsset(ctx context.Context, req *admin_pb.RefetchAssetRequest) (*admin_pb.RefetchAssetResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.RefetchAsset(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) GetAssetReferences(ctx context.Context, req *admin_pb.GetAssetReferencesRequest) (*admin_pb.GetAssetReferencesResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.GetAssetReferences(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) ListAssets(ctx context.Context, req *admin_pb.ListAssetsRequest) (*admin_pb.ListAssetsResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.ListAssets(ctx, req)
+}
+
+func (s *authorizingAssetAdminServer) GetAssetStatistics(ctx context.Context, req *admin_pb.GetAssetStatisticsRequest) (*admin_pb.GetAssetStatisticsResponse, error) {
+	if err := s.authorize(ctx, req.InstanceName); err != nil {
+		return nil, err
+	}
+	return s.server.GetAssetStatistics(ctx, req)
+}
@@ -0,0 +1,50 @@
+package admin_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/admin"
+	admin_pb "github.com/buildbarn/bb-remote-asset/pkg/proto/admin"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizingAssetAdminServer(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("instance")
+	instanceSlice := []digest.InstanceName{instanceName}
+	uri := "https://example.com/example.tar.gz"
+	request := &admin_pb.InvalidateAssetRequest{
+		InstanceName: "instance",
+		Uris:         []string{uri},
+	}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	adminServer := admin.NewAuthorizingAssetAdminServer(
+		admin.NewAssetAdminServer(assetStore, nil, nil, mock.NewMockFetcher(ctrl), clock.SystemClock),
+		authorizer)
+
+	t.Run("Allowed", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{nil})
+		assetStore.EXPECT().Delete(ctx, storage.NewAssetReference([]string{uri}, nil), instanceName)
+
+		_, err := adminServer.InvalidateAsset(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		authorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{status.Error(codes.PermissionDenied, "None shall pass")})
+
+		_, err := adminServer.InvalidateAsset(ctx, request)
+		require.Equal(t, status.Error(codes.PermissionDenied, "None shall pass"), err)
+	})
+}
@@ -1,30 +1,111 @@
 package configuration
 
 import (
+	"context"
+	"database/sql"
+
 	pb "github.com/buildbarn/bb-remote-asset/pkg/proto/configuration/bb_remote_asset"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
 	asset_configuration "github.com/buildbarn/bb-remote-asset/pkg/storage/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/auth"
 	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/grpc"
 	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+
+	// Register the SQLite database driver.
+	_ "modernc.org/sqlite"
 )
 
+// AssetStoreInfo holds an Asset Store created from a configuration,
+// together with the capabilities of its backend.
+type AssetStoreInfo struct {
+	AssetStore storage.AssetStore
+
+	// The backend of the Asset Store, if it supports enumeration.
+	// Calls against it are not subject to authorization.
+	EnumerableAssetStore storage.EnumerableAssetStore
+}
+
 // NewAssetStoreFromConfiguration creates an Asset Store from a
-// configuration and CAS
+// configuration and CAS. If a Reference Index is provided, it is
+// updated whenever an asset is stored. The Freshness Policy determines
+// for how long expired assets are retained by backends that discard
+// them by themselves.
 func NewAssetStoreFromConfiguration(
 	configuration *pb.AssetCacheConfiguration,
 	contentAddressableStorage *blobstore_configuration.BlobAccessInfo,
 	grpcClientFactory grpc.ClientFactory,
 	maximumMessageSizeBytes int,
 	dependenciesGroup program.Group,
+	freshnessPolicy storage.FreshnessPolicy,
 	fetchAuthorizer auth.Authorizer,
 	pushAuthorizer auth.Authorizer,
+	deleteAuthorizer auth.Authorizer,
+	referenceIndex storage.ReferenceIndex,
+) (AssetStoreInfo, error) {
+	assetStore, enumerableAssetStore, err := newAssetStoreBackendFromConfiguration(
+		configuration,
+		contentAddressableStorage,
+		grpcClientFactory,
+		maximumMessageSizeBytes,
+		dependenciesGroup,
+		freshnessPolicy)
+	if err != nil {
+		return AssetStoreInfo{}, err
+	}
+	if referenceIndex != nil {
+		assetStore = storage.NewIndexingAssetStore(assetStore, referenceIndex)
+	}
+	return AssetStoreInfo{
+		AssetStore:           storage.NewAuthorizingAssetStore(assetStore, fetchAuthorizer, pushAuthorizer, deleteAuthorizer),
+		EnumerableAssetStore: enumerableAssetStore,
+	}, nil
+}
+
+// newNestedAssetStoreBackendFromConfiguration creates the Asset Store
+// of a tier or mirror. Such stores are only used through the
+// composite store, meaning that they can't have a Reference Index or
+// be enumerated and swept.
+func newNestedAssetStoreBackendFromConfiguration(
+	configuration *pb.AssetCacheConfiguration,
+	contentAddressableStorage *blobstore_configuration.BlobAccessInfo,
+	grpcClientFactory grpc.ClientFactory,
+	maximumMessageSizeBytes int,
+	dependenciesGroup program.Group,
+	freshnessPolicy storage.FreshnessPolicy,
 ) (storage.AssetStore, error) {
-	var assetStore storage.AssetStore
+	if configuration == nil {
+		return nil, status.Error(codes.InvalidArgument, "Asset Cache configuration is missing")
+	}
+	if configuration.ReferenceIndex != nil {
+		return nil, status.Error(codes.InvalidArgument, "Reference Indexes can only be configured for the top-level Asset Cache")
+	}
+	if configuration.ExpiredAssetSweeper != nil {
+		return nil, status.Error(codes.InvalidArgument, "Expired asset sweepers can only be configured for the top-level Asset Cache")
+	}
+	assetStore, _, err := newAssetStoreBackendFromConfiguration(
+		configuration,
+		contentAddressableStorage,
+		grpcClientFactory,
+		maximumMessageSizeBytes,
+		dependenciesGroup,
+		freshnessPolicy)
+	return assetStore, err
+}
+
+func newAssetStoreBackendFromConfiguration(
+	configuration *pb.AssetCacheConfiguration,
+	contentAddressableStorage *blobstore_configuration.BlobAccessInfo,
+	grpcClientFactory grpc.ClientFactory,
+	maximumMessageSizeBytes int,
+	dependenciesGroup program.Group,
+	freshnessPolicy storage.FreshnessPolicy,
+) (storage.AssetStore, storage.EnumerableAssetStore, error) {
 	switch backend := configuration.Backend.(type) {
 	case *pb.AssetCacheConfiguration_BlobAccess:
 		assetBlobAccessCreator := asset_configuration.NewAssetBlobAccessCreator(grpcClientFactory, maximumMessageSizeBytes)
@@ -34,9 +115,9 @@ func NewAssetStoreFromConfiguration(
 			backend.BlobAccess,
 			assetBlobAccessCreator)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		assetStore = storage.NewBlobAccessAssetStore(assetBlobAccess.BlobAccess, maximumMessageSizeBytes)
+		return storage.NewBlobAccessAssetStore(assetBlobAccess.BlobAccess, maximumMessageSizeBytes), nil, nil
 	case *pb.AssetCacheConfiguration_ActionCache:
 		actionCache, err := blobstore_configuration.NewBlobAccessFromConfiguration(
 			dependenciesGroup,
@@ -46,11 +127,73 @@ func NewAssetStoreFromConfiguration(
 				grpcClientFactory,
 				maximumMessageSizeBytes))
 		if err != nil {
-			return nil, err
+			return nil, nil, err
+		}
+		return storage.NewActionCacheAssetStore(actionCache.BlobAccess, contentAddressableStorage.BlobAccess, maximumMessageSizeBytes), nil, nil
+	case *pb.AssetCacheConfiguration_Memory:
+		if backend.Memory.MaximumEntries <= 0 || backend.Memory.MaximumSizeBytes <= 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "The maximum number of entries and size of the memory asset cache must be positive")
+		}
+		return storage.NewMemoryAssetStore(
+			int(backend.Memory.MaximumEntries),
+			backend.Memory.MaximumSizeBytes,
+			clock.SystemClock,
+			freshnessPolicy.GetMaximumStaleWhileRevalidate()), nil, nil
+	case *pb.AssetCacheConfiguration_Sqlite:
+		db, err := sql.Open("sqlite", "file:"+backend.Sqlite.Path+"?_pragma=journal_mode(WAL)")
+		if err != nil {
+			return nil, nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to open SQLite database")
+		}
+		// SQLite only permits a single writer at a time. Serialize
+		// access, as opposed to letting concurrent writers fail.
+		db.SetMaxOpenConns(1)
+		assetStore, err := storage.NewSQLiteAssetStore(context.Background(), db, clock.SystemClock)
+		if err != nil {
+			return nil, nil, err
+		}
+		return assetStore, assetStore, nil
+	case *pb.AssetCacheConfiguration_Tiered:
+		if len(backend.Tiered.Tiers) == 0 {
+			return nil, nil, status.Error(codes.InvalidArgument, "Tiered Asset Cache requires at least one tier")
+		}
+		tiers := make([]storage.AssetStore, 0, len(backend.Tiered.Tiers))
+		for i, tierConfiguration := range backend.Tiered.Tiers {
+			tier, err := newNestedAssetStoreBackendFromConfiguration(
+				tierConfiguration,
+				contentAddressableStorage,
+				grpcClientFactory,
+				maximumMessageSizeBytes,
+				dependenciesGroup,
+				freshnessPolicy)
+			if err != nil {
+				return nil, nil, util.StatusWrapf(err, "Tier %d", i)
+			}
+			tiers = append(tiers, tier)
+		}
+		return storage.NewTieredAssetStore(tiers), nil, nil
+	case *pb.AssetCacheConfiguration_Mirrored:
+		storeA, err := newNestedAssetStoreBackendFromConfiguration(
+			backend.Mirrored.BackendA,
+			contentAddressableStorage,
+			grpcClientFactory,
+			maximumMessageSizeBytes,
+			dependenciesGroup,
+			freshnessPolicy)
+		if err != nil {
+			return nil, nil, util.StatusWrap(err, "Backend A")
+		}
+		storeB, err := newNestedAssetStoreBackendFromConfiguration(
+			backend.Mirrored.BackendB,
+			contentAddressableStorage,
+			grpcClientFactory,
+			maximumMessageSizeBytes,
+			dependenciesGroup,
+			freshnessPolicy)
+		if err != nil {
+			return nil, nil, util.StatusWrap(err, "Backend B")
 		}
-		assetStore = storage.NewActionCacheAssetStore(actionCache.BlobAccess, contentAddressableStorage.BlobAccess, maximumMessageSizeBytes)
+		return storage.NewMirroredAssetStore(storeA, storeB), nil, nil
 	default:
-		return nil, status.Errorf(codes.InvalidArgument, "Asset Cache configuration is invalid as no supported Asset Cache is defined.")
+		return nil, nil, status.Errorf(codes.InvalidArgument, "Asset Cache configuration is invalid as no supported Asset Cache is defined.")
 	}
-	return storage.NewAuthorizingAssetStore(assetStore, fetchAuthorizer, pushAuthorizer), nil
 }
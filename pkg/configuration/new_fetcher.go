@@ -2,6 +2,7 @@ package configuration
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
 	pb "github.com/buildbarn/bb-remote-asset/pkg/proto/configuration/bb_remote_asset/fetch"
@@ -11,6 +12,7 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/grpc"
 	bb_http "github.com/buildbarn/bb-storage/pkg/http"
+	"github.com/buildbarn/bb-storage/pkg/util"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -20,6 +22,7 @@ import (
 // server from a jsonnet configuration.
 func NewFetcherFromConfiguration(configuration *pb.FetcherConfiguration,
 	assetStore storage.AssetStore,
+	freshnessPolicy storage.FreshnessPolicy,
 	contentAddressableStorage blobstore.BlobAccess,
 	grpcClientFactory grpc.ClientFactory,
 	maximumMessageSizeBytes int,
@@ -35,9 +38,34 @@ func NewFetcherFromConfiguration(configuration *pb.FetcherConfiguration,
 			if err != nil {
 				return nil, err
 			}
+			retryPolicy, err := newHTTPRetryPolicyFromConfiguration(backend.Http.RetryPolicy)
+			if err != nil {
+				return nil, util.StatusWrap(err, "Invalid HTTP retry policy")
+			}
+			credentialProvider, err := newHTTPCredentialProviderFromConfiguration(backend.Http)
+			if err != nil {
+				return nil, util.StatusWrap(err, "Invalid HTTP credentials")
+			}
+			credentialPrecedence, ok := httpCredentialPrecedences[backend.Http.CredentialPrecedence]
+			if !ok {
+				return nil, status.Errorf(codes.InvalidArgument, "Unknown HTTP credential precedence %s", backend.Http.CredentialPrecedence)
+			}
+			maximumInMemoryBodySizeBytes := backend.Http.MaximumInMemoryBodySizeBytes
+			if maximumInMemoryBodySizeBytes < 0 {
+				return nil, status.Error(codes.InvalidArgument, "Maximum in-memory HTTP response body size cannot be negative")
+			} else if maximumInMemoryBodySizeBytes == 0 {
+				maximumInMemoryBodySizeBytes = defaultMaximumInMemoryBodySizeBytes
+			}
 			fetcher = fetch.NewHTTPFetcher(
 				&http.Client{Transport: roundTripper},
-				contentAddressableStorage)
+				contentAddressableStorage,
+				maximumInMemoryBodySizeBytes,
+				backend.Http.SpoolDirectoryPath,
+				retryPolicy,
+				int(backend.Http.MaximumResumeAttempts),
+				credentialProvider,
+				credentialPrecedence,
+				clock.SystemClock)
 		case *pb.FetcherConfiguration_Error:
 			fetcher = fetch.NewErrorFetcher(backend.Error)
 		case *pb.FetcherConfiguration_RemoteExecution:
@@ -49,10 +77,62 @@ func NewFetcherFromConfiguration(configuration *pb.FetcherConfiguration,
 		default:
 			return nil, status.Errorf(codes.InvalidArgument, "Fetcher configuration is invalid as no supported Fetchers are defined.")
 		}
+
+		var defaultTimeout, maximumTimeout time.Duration
+		if configuration.DefaultTimeout != nil {
+			if err := configuration.DefaultTimeout.CheckValid(); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid default timeout")
+			}
+			defaultTimeout = configuration.DefaultTimeout.AsDuration()
+		}
+		if configuration.MaximumTimeout != nil {
+			if err := configuration.MaximumTimeout.CheckValid(); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid maximum timeout")
+			}
+			maximumTimeout = configuration.MaximumTimeout.AsDuration()
+		}
+		fetcher = fetch.NewTimeoutFetcher(fetcher, clock.SystemClock, defaultTimeout, maximumTimeout)
+
+		if configuration.UrlRewriteRules != "" {
+			urlRewriter, err := fetch.NewURLRewriterFromConfiguration(configuration.UrlRewriteRules)
+			if err != nil {
+				return nil, util.StatusWrap(err, "Invalid URL rewrite rules")
+			}
+			fetcher = fetch.NewURLRewritingFetcher(fetcher, urlRewriter)
+		}
+
+		if configuration.NegativeCacheDuration != nil {
+			if err := configuration.NegativeCacheDuration.CheckValid(); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid negative cache duration")
+			}
+			fetcher = fetch.NewNegativeCachingFetcher(fetcher, clock.SystemClock, configuration.NegativeCacheDuration.AsDuration())
+		}
 	}
 	if assetStore != nil {
-		fetcher = fetch.NewCachingFetcher(fetcher, assetStore)
+		completenessChecker := fetch.NoCompletenessChecking
+		if completenessChecking := configuration.GetCompletenessChecking(); completenessChecking != nil {
+			if completenessChecking.BatchSize <= 0 {
+				return nil, status.Error(codes.InvalidArgument, "Completeness checking batch size must be positive")
+			}
+			var refreshInterval time.Duration
+			if completenessChecking.RefreshInterval != nil {
+				if err := completenessChecking.RefreshInterval.CheckValid(); err != nil {
+					return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid completeness checking refresh interval")
+				}
+				refreshInterval = completenessChecking.RefreshInterval.AsDuration()
+			}
+			completenessChecker = fetch.NewCASCompletenessChecker(
+				contentAddressableStorage,
+				int(completenessChecking.BatchSize),
+				maximumMessageSizeBytes,
+				clock.SystemClock,
+				refreshInterval)
+		}
+		fetcher = fetch.NewCachingFetcher(fetcher, assetStore, completenessChecker, freshnessPolicy)
 	}
+	// Merge concurrent requests for the same asset, so that cache
+	// misses only cause the asset to be downloaded once.
+	fetcher = fetch.NewCoalescingFetcher(fetcher)
 	return fetch.NewAuthorizingFetcher(
 		fetch.NewMetricsFetcher(
 			fetch.NewLoggingFetcher(
@@ -64,3 +144,94 @@ func NewFetcherFromConfiguration(configuration *pb.FetcherConfiguration,
 		authorizer,
 	), nil
 }
+
+func newHTTPRetryPolicyFromConfiguration(configuration *pb.FetcherConfiguration_HttpRetryPolicy) (fetch.HTTPRetryPolicy, error) {
+	if configuration == nil || configuration.MaximumAttempts <= 1 {
+		return fetch.NoHTTPRetries, nil
+	}
+	retryPolicy := fetch.HTTPRetryPolicy{
+		MaximumAttempts:      int(configuration.MaximumAttempts),
+		BackoffMultiplier:    configuration.BackoffMultiplier,
+		RetryableStatusCodes: map[int]struct{}{},
+		RetryTransportErrors: configuration.RetryTransportErrors,
+	}
+	if configuration.InitialBackoff != nil {
+		if err := configuration.InitialBackoff.CheckValid(); err != nil {
+			return fetch.HTTPRetryPolicy{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid initial backoff")
+		}
+		retryPolicy.InitialBackoff = configuration.InitialBackoff.AsDuration()
+	}
+	if configuration.MaximumBackoff != nil {
+		if err := configuration.MaximumBackoff.CheckValid(); err != nil {
+			return fetch.HTTPRetryPolicy{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid maximum backoff")
+		}
+		retryPolicy.MaximumBackoff = configuration.MaximumBackoff.AsDuration()
+	}
+	for _, statusCode := range configuration.RetryableStatusCodes {
+		retryPolicy.RetryableStatusCodes[int(statusCode)] = struct{}{}
+	}
+	return retryPolicy, nil
+}
+
+// The maximum number of bytes of a HTTP response body that is held in
+// memory if no limit is configured. Larger bodies are spooled to disk.
+const defaultMaximumInMemoryBodySizeBytes = 16 * 1024 * 1024
+
+var httpCredentialPrecedences = map[pb.FetcherConfiguration_HttpCredentialPrecedence]fetch.HTTPCredentialPrecedence{
+	pb.FetcherConfiguration_CLIENT_OVERRIDES_SERVER: fetch.HTTPCredentialPrecedenceClient,
+	pb.FetcherConfiguration_SERVER_OVERRIDES_CLIENT: fetch.HTTPCredentialPrecedenceServer,
+	pb.FetcherConfiguration_SERVER_EXCLUSIVE:        fetch.HTTPCredentialPrecedenceServerExclusive,
+}
+
+func newHTTPCredentialProviderFromConfiguration(configuration *pb.FetcherConfiguration_HttpFetcherConfiguration) (fetch.HTTPCredentialProvider, error) {
+	var providers []fetch.HTTPCredentialProvider
+	for i, credentials := range configuration.Credentials {
+		var provider fetch.HTTPCredentialProvider
+		switch c := credentials.Credentials.(type) {
+		case *pb.FetcherConfiguration_HttpCredentials_Headers:
+			header := http.Header{}
+			for name, value := range c.Headers.Headers {
+				header.Set(name, value)
+			}
+			provider = fetch.NewStaticHTTPCredentialProvider(header)
+		case *pb.FetcherConfiguration_HttpCredentials_BasicAuthentication:
+			provider = fetch.NewBasicAuthenticationHTTPCredentialProvider(c.BasicAuthentication.Username, c.BasicAuthentication.Password)
+		case *pb.FetcherConfiguration_HttpCredentials_BearerTokenPath:
+			provider = fetch.NewBearerTokenFileHTTPCredentialProvider(c.BearerTokenPath)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Credentials at index %d do not specify any credentials", i)
+		}
+
+		switch m := credentials.Match.(type) {
+		case *pb.FetcherConfiguration_HttpCredentials_Host:
+			provider = fetch.NewHostMatchingHTTPCredentialProvider(provider, m.Host)
+		case *pb.FetcherConfiguration_HttpCredentials_UrlPrefix:
+			provider = fetch.NewURLPrefixMatchingHTTPCredentialProvider(provider, m.UrlPrefix)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Credentials at index %d do not specify a host or URL prefix", i)
+		}
+		providers = append(providers, provider)
+	}
+	if configuration.CredentialHelperPath != "" {
+		var cacheDuration time.Duration
+		if configuration.CredentialHelperCacheDuration != nil {
+			if err := configuration.CredentialHelperCacheDuration.CheckValid(); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid credential helper cache duration")
+			}
+			cacheDuration = configuration.CredentialHelperCacheDuration.AsDuration()
+		}
+		providers = append(providers, fetch.NewCredentialHelperHTTPCredentialProvider(configuration.CredentialHelperPath, cacheDuration, clock.SystemClock))
+	}
+	if configuration.NetrcPath != "" {
+		providers = append(providers, fetch.NewNetrcHTTPCredentialProvider(configuration.NetrcPath))
+	}
+
+	switch len(providers) {
+	case 0:
+		return fetch.NoHTTPCredentials, nil
+	case 1:
+		return providers[0], nil
+	default:
+		return fetch.NewFirstMatchHTTPCredentialProvider(providers), nil
+	}
+}
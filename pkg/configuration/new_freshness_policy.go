@@ -0,0 +1,79 @@
+package configuration
+
+import (
+	pb "github.com/buildbarn/bb-remote-asset/pkg/proto/configuration/bb_remote_asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewFreshnessPolicyFromConfiguration creates a Freshness Policy from
+// a configuration. If no configuration is provided, assets only expire
+// if an expiration time is provided when pushing them.
+func NewFreshnessPolicyFromConfiguration(configuration *pb.AssetFreshnessConfiguration) (storage.FreshnessPolicy, error) {
+	defaultParameters, err := newFreshnessParametersFromConfiguration(configuration.GetDefaultPolicy())
+	if err != nil {
+		return nil, util.StatusWrap(err, "Invalid default policy")
+	}
+	instanceParameters := map[digest.InstanceName]storage.FreshnessParameters{}
+	for name, policy := range configuration.GetInstanceNamePolicies() {
+		instanceName, err := digest.NewInstanceName(name)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid instance name %#v", name)
+		}
+		parameters, err := newFreshnessParametersFromConfiguration(policy)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Invalid policy for instance name %#v", name)
+		}
+		instanceParameters[instanceName] = parameters
+	}
+	return storage.NewFreshnessPolicy(clock.SystemClock, defaultParameters, instanceParameters), nil
+}
+
+func newFreshnessParametersFromConfiguration(configuration *pb.AssetFreshnessPolicy) (storage.FreshnessParameters, error) {
+	var parameters storage.FreshnessParameters
+	if configuration.GetDefaultTtl() != nil {
+		if err := configuration.DefaultTtl.CheckValid(); err != nil {
+			return storage.FreshnessParameters{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid default TTL")
+		}
+		parameters.DefaultTTL = configuration.DefaultTtl.AsDuration()
+		if parameters.DefaultTTL <= 0 {
+			return storage.FreshnessParameters{}, status.Error(codes.InvalidArgument, "Default TTL must be positive")
+		}
+	}
+	if configuration.GetMaximumTtl() != nil {
+		if err := configuration.MaximumTtl.CheckValid(); err != nil {
+			return storage.FreshnessParameters{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid maximum TTL")
+		}
+		parameters.MaximumTTL = configuration.MaximumTtl.AsDuration()
+		if parameters.MaximumTTL <= 0 {
+			return storage.FreshnessParameters{}, status.Error(codes.InvalidArgument, "Maximum TTL must be positive")
+		}
+		if parameters.DefaultTTL > parameters.MaximumTTL {
+			return storage.FreshnessParameters{}, status.Error(codes.InvalidArgument, "Default TTL must not exceed the maximum TTL")
+		}
+	}
+	if configuration.GetStaleWhileRevalidate() != nil {
+		if err := configuration.StaleWhileRevalidate.CheckValid(); err != nil {
+			return storage.FreshnessParameters{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid stale while revalidate duration")
+		}
+		parameters.StaleWhileRevalidate = configuration.StaleWhileRevalidate.AsDuration()
+		if parameters.StaleWhileRevalidate < 0 {
+			return storage.FreshnessParameters{}, status.Error(codes.InvalidArgument, "Stale while revalidate duration cannot be negative")
+		}
+	}
+	if configuration.GetRevalidateAhead() != nil {
+		if err := configuration.RevalidateAhead.CheckValid(); err != nil {
+			return storage.FreshnessParameters{}, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid revalidate ahead duration")
+		}
+		parameters.RevalidateAhead = configuration.RevalidateAhead.AsDuration()
+		if parameters.RevalidateAhead < 0 {
+			return storage.FreshnessParameters{}, status.Error(codes.InvalidArgument, "Revalidate ahead duration cannot be negative")
+		}
+	}
+	return parameters, nil
+}
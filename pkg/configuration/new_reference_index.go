@@ -0,0 +1,37 @@
+package configuration
+
+import (
+	pb "github.com/buildbarn/bb-remote-asset/pkg/proto/configuration/bb_remote_asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	asset_configuration "github.com/buildbarn/bb-remote-asset/pkg/storage/blobstore"
+	blobstore_configuration "github.com/buildbarn/bb-storage/pkg/blobstore/configuration"
+	"github.com/buildbarn/bb-storage/pkg/grpc"
+	"github.com/buildbarn/bb-storage/pkg/program"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewReferenceIndexFromConfiguration creates a Reference Index from a
+// configuration
+func NewReferenceIndexFromConfiguration(
+	configuration *pb.ReferenceIndexConfiguration,
+	grpcClientFactory grpc.ClientFactory,
+	maximumMessageSizeBytes int,
+	dependenciesGroup program.Group,
+) (storage.ReferenceIndex, error) {
+	if configuration.MaximumReferencesPerDigest <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "The maximum number of references per digest must be positive")
+	}
+	referenceIndexBlobAccess, err := blobstore_configuration.NewBlobAccessFromConfiguration(
+		dependenciesGroup,
+		configuration.BlobAccess,
+		asset_configuration.NewReferenceIndexBlobAccessCreator(grpcClientFactory, maximumMessageSizeBytes))
+	if err != nil {
+		return nil, err
+	}
+	return storage.NewBlobAccessReferenceIndex(
+		referenceIndexBlobAccess.BlobAccess,
+		maximumMessageSizeBytes,
+		int(configuration.MaximumReferencesPerDigest)), nil
+}
@@ -0,0 +1,209 @@
+package fetch
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+	"os"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/klauspost/compress/zstd"
+	"github.com/ulikunitz/xz"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// archiveFormat is the type of archive that is downloaded by
+// FetchDirectory.
+type archiveFormat int
+
+const (
+	archiveFormatUnknown archiveFormat = iota
+	archiveFormatTar
+	archiveFormatTarGzip
+	archiveFormatTarBzip2
+	archiveFormatTarXz
+	archiveFormatTarZstd
+	archiveFormatZip
+)
+
+// archiveFormatNames maps the values of the 'bazel.archive_type'
+// qualifier to archive formats. The names match those accepted by the
+// 'type' attribute of Bazel's http_archive() rule.
+var archiveFormatNames = map[string]archiveFormat{
+	"tar":     archiveFormatTar,
+	"tar.gz":  archiveFormatTarGzip,
+	"tgz":     archiveFormatTarGzip,
+	"tar.bz2": archiveFormatTarBzip2,
+	"tbz":     archiveFormatTarBzip2,
+	"tar.xz":  archiveFormatTarXz,
+	"txz":     archiveFormatTarXz,
+	"tar.zst": archiveFormatTarZstd,
+	"tzst":    archiveFormatTarZstd,
+	"zip":     archiveFormatZip,
+	"jar":     archiveFormatZip,
+	"war":     archiveFormatZip,
+	"aar":     archiveFormatZip,
+}
+
+// getArchiveFormat returns the archive format requested through the
+// 'bazel.archive_type' qualifier. If the qualifier is absent, the
+// format is detected from the contents of the archive.
+func getArchiveFormat(qualifiers []*remoteasset.Qualifier) (archiveFormat, error) {
+	for _, qualifier := range qualifiers {
+		if qualifier.Name == "bazel.archive_type" {
+			if format, ok := archiveFormatNames[qualifier.Value]; ok {
+				return format, nil
+			}
+			return archiveFormatUnknown, status.Errorf(codes.InvalidArgument, "Unsupported archive type %#v", qualifier.Value)
+		}
+	}
+	return archiveFormatUnknown, nil
+}
+
+// sniffArchiveFormat determines the format of an archive by inspecting
+// its leading bytes. Compressed streams are assumed to contain a tar
+// archive.
+func sniffArchiveFormat(r io.ReaderAt) archiveFormat {
+	var header [512]byte
+	n, _ := r.ReadAt(header[:], 0)
+	h := header[:n]
+	switch {
+	case bytes.HasPrefix(h, []byte{0x1f, 0x8b}):
+		return archiveFormatTarGzip
+	case bytes.HasPrefix(h, []byte("BZh")):
+		return archiveFormatTarBzip2
+	case bytes.HasPrefix(h, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return archiveFormatTarXz
+	case bytes.HasPrefix(h, []byte{0x28, 0xb5, 0x2f, 0xfd}):
+		return archiveFormatTarZstd
+	case bytes.HasPrefix(h, []byte("PK\x03\x04")), bytes.HasPrefix(h, []byte("PK\x05\x06")):
+		return archiveFormatZip
+	case len(h) >= 262 && bytes.Equal(h[257:262], []byte("ustar")):
+		return archiveFormatTar
+	}
+	return archiveFormatUnknown
+}
+
+// archiveFilePutter stores the contents of a file contained in an
+// archive in the CAS, returning its digest.
+type archiveFilePutter func(r io.Reader) (bb_digest.Digest, error)
+
+// extractArchive extracts the contents of an archive into a
+// directoryBuilder, storing the contents of all files in the CAS.
+func extractArchive(r io.ReaderAt, sizeBytes int64, format archiveFormat, root *directoryBuilder, putFile archiveFilePutter) error {
+	if format == archiveFormatUnknown {
+		format = sniffArchiveFormat(r)
+	}
+
+	var tarStream io.Reader = io.NewSectionReader(r, 0, sizeBytes)
+	switch format {
+	case archiveFormatTar:
+	case archiveFormatTarGzip:
+		gzipReader, err := gzip.NewReader(tarStream)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create gzip reader")
+		}
+		defer gzipReader.Close()
+		tarStream = gzipReader
+	case archiveFormatTarBzip2:
+		tarStream = bzip2.NewReader(tarStream)
+	case archiveFormatTarXz:
+		xzReader, err := xz.NewReader(tarStream)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create xz reader")
+		}
+		tarStream = xzReader
+	case archiveFormatTarZstd:
+		zstdReader, err := zstd.NewReader(tarStream)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to create zstd reader")
+		}
+		defer zstdReader.Close()
+		tarStream = zstdReader
+	case archiveFormatZip:
+		return extractZip(r, sizeBytes, root, putFile)
+	default:
+		return status.Error(codes.InvalidArgument, "Unable to determine archive type")
+	}
+	return extractTar(tarStream, root, putFile)
+}
+
+func extractTar(r io.Reader, root *directoryBuilder, putFile archiveFilePutter) error {
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to read tar archive")
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = root.AddDirectory(header.Name)
+		case tar.TypeReg, tar.TypeRegA:
+			var digest bb_digest.Digest
+			if digest, err = putFile(tarReader); err == nil {
+				err = root.AddFile(header.Name, digest, header.Mode&0o111 != 0)
+			}
+		case tar.TypeSymlink:
+			err = root.AddSymlink(header.Name, header.Linkname)
+		case tar.TypeLink:
+			err = root.AddHardlink(header.Name, header.Linkname)
+		default:
+			// Skip device nodes, FIFOs, etc., which cannot be
+			// represented in the CAS.
+		}
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to extract %#v", header.Name)
+		}
+	}
+}
+
+func extractZip(r io.ReaderAt, sizeBytes int64, root *directoryBuilder, putFile archiveFilePutter) error {
+	zipReader, err := zip.NewReader(r, sizeBytes)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to read zip archive")
+	}
+	for _, file := range zipReader.File {
+		if err := extractZipFile(file, root, putFile); err != nil {
+			return util.StatusWrapf(err, "Failed to extract %#v", file.Name)
+		}
+	}
+	return nil
+}
+
+func extractZipFile(file *zip.File, root *directoryBuilder, putFile archiveFilePutter) error {
+	mode := file.Mode()
+	if mode.IsDir() {
+		return root.AddDirectory(file.Name)
+	}
+	if mode&os.ModeType != 0 && mode&os.ModeSymlink == 0 {
+		// Skip device nodes, FIFOs, etc.
+		return nil
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to open file")
+	}
+	defer f.Close()
+	if mode&os.ModeSymlink != 0 {
+		target, err := io.ReadAll(io.LimitReader(f, 4096))
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to read symbolic link target")
+		}
+		return root.AddSymlink(file.Name, string(target))
+	}
+	digest, err := putFile(f)
+	if err != nil {
+		return err
+	}
+	return root.AddFile(file.Name, digest, mode&0o111 != 0)
+}
@@ -3,6 +3,7 @@ package fetch
 import (
 	"encoding/json"
 	"net/http"
+	"strings"
 )
 
 // AuthHeaders is a map from target URI to headers to be applied for the request
@@ -15,6 +16,17 @@ func NewAuthHeadersFromQualifier(value string) (*AuthHeaders, error) {
 	return &ah, err
 }
 
+// NewAuthHeadersFromHeader creates an AuthHeaders that applies a set of
+// HTTP headers to requests for a single URI. Headers having multiple
+// values are joined into a single comma separated value.
+func NewAuthHeadersFromHeader(uri string, header http.Header) AuthHeaders {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[name] = strings.Join(values, ", ")
+	}
+	return AuthHeaders{uri: headers}
+}
+
 // ApplyHeaders mutates a http.Request to apply headers requested by the client.
 func (ah AuthHeaders) ApplyHeaders(uri string, req *http.Request) {
 	if headers, ok := ah[uri]; ok {
@@ -0,0 +1,122 @@
+package fetch
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+)
+
+// bodySpool is an io.Writer that accumulates the contents of an HTTP
+// response body. Data is kept in memory until a configurable limit is
+// exceeded, after which all data is moved into a temporary file. This
+// permits computing the digest of a response body of unknown size,
+// without having to hold all of it in memory.
+type bodySpool struct {
+	maximumInMemorySizeBytes int64
+	directoryPath            string
+
+	memory    bytes.Buffer
+	file      *os.File
+	sizeBytes int64
+}
+
+func newBodySpool(maximumInMemorySizeBytes int64, directoryPath string) *bodySpool {
+	return &bodySpool{
+		maximumInMemorySizeBytes: maximumInMemorySizeBytes,
+		directoryPath:            directoryPath,
+	}
+}
+
+func (bs *bodySpool) Write(p []byte) (int, error) {
+	if bs.file == nil && bs.sizeBytes+int64(len(p)) > bs.maximumInMemorySizeBytes {
+		// The in-memory limit is about to be exceeded. Move the
+		// data received so far into a temporary file.
+		f, err := os.CreateTemp(bs.directoryPath, "bb_remote_asset_spool_*")
+		if err != nil {
+			return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to create temporary file for spooling response body")
+		}
+		bs.file = f
+		if _, err := bs.memory.WriteTo(f); err != nil {
+			return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to write to spool file")
+		}
+		bs.memory = bytes.Buffer{}
+	}
+
+	var n int
+	var err error
+	if bs.file == nil {
+		n, err = bs.memory.Write(p)
+	} else if n, err = bs.file.Write(p); err != nil {
+		err = util.StatusWrapWithCode(err, codes.Internal, "Failed to write to spool file")
+	}
+	bs.sizeBytes += int64(n)
+	return n, err
+}
+
+// SizeBytes returns the number of bytes written into the spool.
+func (bs *bodySpool) SizeBytes() int64 {
+	return bs.sizeBytes
+}
+
+// ToBuffer converts the contents of the spool to a Buffer. As the
+// caller is expected to have computed the digest of the data while it
+// was being written, the resulting buffer is not validated once more.
+// Ownership of the temporary file, if any, is transferred to the
+// buffer, meaning it is removed once the buffer is released.
+func (bs *bodySpool) ToBuffer() buffer.Buffer {
+	if bs.file == nil {
+		return buffer.NewValidatedBufferFromByteSlice(bs.memory.Bytes())
+	}
+	f := bs.file
+	bs.file = nil
+	return buffer.NewValidatedBufferFromReaderAt(&spoolFile{File: f}, bs.sizeBytes)
+}
+
+// ToReaderAt provides random access to the contents of the spool.
+// Ownership of the temporary file, if any, is transferred to the
+// returned object, meaning it is removed once it is closed.
+func (bs *bodySpool) ToReaderAt() buffer.ReadAtCloser {
+	if bs.file == nil {
+		return nopReadAtCloser{Reader: bytes.NewReader(bs.memory.Bytes())}
+	}
+	f := bs.file
+	bs.file = nil
+	return &spoolFile{File: f}
+}
+
+// Discard releases all resources associated with the spool. It should
+// be called when the contents of the spool are no longer needed and
+// ToBuffer() has not been called.
+func (bs *bodySpool) Discard() {
+	if bs.file != nil {
+		(&spoolFile{File: bs.file}).Close()
+		bs.file = nil
+	}
+	bs.memory = bytes.Buffer{}
+}
+
+// nopReadAtCloser adds a no-op Close() method to a bytes.Reader.
+type nopReadAtCloser struct {
+	*bytes.Reader
+}
+
+func (nopReadAtCloser) Close() error {
+	return nil
+}
+
+// spoolFile is a temporary file that is removed upon closure.
+type spoolFile struct {
+	*os.File
+}
+
+func (sf *spoolFile) Close() error {
+	err := sf.File.Close()
+	if removeErr := os.Remove(sf.File.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
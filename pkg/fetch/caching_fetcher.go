@@ -2,31 +2,50 @@ package fetch
 
 import (
 	"context"
+	"log"
+	"sync"
 	"time"
 
 	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
 	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/util"
-	"google.golang.org/protobuf/types/known/timestamppb"
 
 	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"google.golang.org/protobuf/proto"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
 type cachingFetcher struct {
-	fetcher    Fetcher
-	assetStore storage.AssetStore
+	fetcher             Fetcher
+	assetStore          storage.AssetStore
+	completenessChecker CompletenessChecker
+	freshnessPolicy     storage.FreshnessPolicy
+
+	revalidationsLock sync.Mutex
+	revalidations     map[string]struct{}
 }
 
 // NewCachingFetcher creates a decorator for remoteasset.FetchServer implementations to avoid having to fetch the
-// blob remotely multiple times
-func NewCachingFetcher(fetcher Fetcher, assetStore storage.AssetStore) Fetcher {
+// blob remotely multiple times. Cached assets are only returned if the
+// CompletenessChecker reports that their contents are still present and
+// the FreshnessPolicy reports that they have not expired. The
+// FreshnessPolicy also determines the expiration time of newly fetched
+// assets.
+//
+// Assets that the FreshnessPolicy reports as stale are returned
+// immediately, while they are fetched again in the background to
+// update the asset cache.
+func NewCachingFetcher(fetcher Fetcher, assetStore storage.AssetStore, completenessChecker CompletenessChecker, freshnessPolicy storage.FreshnessPolicy) Fetcher {
 	return &cachingFetcher{
-		fetcher:    fetcher,
-		assetStore: assetStore,
+		fetcher:             fetcher,
+		assetStore:          assetStore,
+		completenessChecker: completenessChecker,
+		freshnessPolicy:     freshnessPolicy,
+		revalidations:       map[string]struct{}{},
 	}
 }
 
@@ -52,12 +71,10 @@ func (cf *cachingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchB
 			continue
 		}
 
-		// Check whether the asset has expired, making sure ExpireAt was set
-		if assetData.ExpireAt != nil {
-			expireTime := assetData.ExpireAt.AsTime()
-			if expireTime.Before(time.Now()) && !expireTime.Equal(time.Unix(0, 0)) {
-				continue
-			}
+		// Check whether the asset has expired
+		freshness := cf.freshnessPolicy.GetFreshness(instanceName, assetData)
+		if freshness == storage.FreshnessExpired {
+			continue
 		}
 
 		// Check that content is newer than the oldest accepted by the request
@@ -68,7 +85,27 @@ func (cf *cachingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchB
 			}
 		}
 
+		// Check that the blob has not been removed from the CAS
+		if err := cf.completenessChecker.CheckBlob(ctx, instanceName, assetData.Digest); err != nil {
+			continue
+		}
+
 		// Successful retrieval from the asset reference cache
+		if freshness == storage.FreshnessStale {
+			// The request is retained by the background
+			// fetch, so it must not be shared with the caller.
+			revalidateReq := proto.Clone(req).(*remoteasset.FetchBlobRequest)
+			cf.revalidate(ctx, "FetchBlob", revalidateReq.InstanceName, revalidateReq.Uris, revalidateReq.Qualifiers, func(ctx context.Context) error {
+				response, err := cf.fetcher.FetchBlob(ctx, revalidateReq)
+				if err != nil {
+					return err
+				}
+				if response.Status.GetCode() != int32(codes.OK) {
+					return status.ErrorProto(response.Status)
+				}
+				return cf.putAsset(ctx, instanceName, revalidateReq.Uris, response.Uri, response.Qualifiers, response.BlobDigest)
+			})
+		}
 		return &remoteasset.FetchBlobResponse{
 			Status:     status.New(codes.OK, "Blob fetched successfully from asset cache").Proto(),
 			Uri:        uri,
@@ -87,22 +124,9 @@ func (cf *cachingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchB
 		return response, nil
 	}
 
-	// Cache fetched blob with single URI
-	assetRef := storage.NewAssetReference([]string{response.Uri}, response.Qualifiers)
-	assetData := storage.NewAsset(response.BlobDigest, getDefaultTimestamp())
-	err = cf.assetStore.Put(ctx, assetRef, assetData, instanceName)
-	if err != nil {
+	if err := cf.putAsset(ctx, instanceName, req.Uris, response.Uri, response.Qualifiers, response.BlobDigest); err != nil {
 		return response, err
 	}
-	if len(req.Uris) > 1 {
-		// Cache fetched blob with list of URIs
-		assetRef = storage.NewAssetReference(req.Uris, assetRef.Qualifiers)
-		err = cf.assetStore.Put(ctx, assetRef, assetData, instanceName)
-		if err != nil {
-			return response, err
-		}
-	}
-
 	return response, nil
 }
 
@@ -125,12 +149,10 @@ func (cf *cachingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.F
 			continue
 		}
 
-		// Check whether the asset has expired, making sure ExpireAt was set
-		if assetData.ExpireAt != nil {
-			expireTime := assetData.ExpireAt.AsTime()
-			if expireTime.Before(time.Now()) && !expireTime.Equal(time.Unix(0, 0)) {
-				continue
-			}
+		// Check whether the asset has expired
+		freshness := cf.freshnessPolicy.GetFreshness(instanceName, assetData)
+		if freshness == storage.FreshnessExpired {
+			continue
 		}
 
 		// Check that content is newer than the oldest accepted by the request
@@ -141,7 +163,27 @@ func (cf *cachingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.F
 			}
 		}
 
+		// Check that the directory has not been removed from the CAS
+		if err := cf.completenessChecker.CheckDirectory(ctx, instanceName, assetData.Digest); err != nil {
+			continue
+		}
+
 		// Successful retrieval from the asset reference cache
+		if freshness == storage.FreshnessStale {
+			// The request is retained by the background
+			// fetch, so it must not be shared with the caller.
+			revalidateReq := proto.Clone(req).(*remoteasset.FetchDirectoryRequest)
+			cf.revalidate(ctx, "FetchDirectory", revalidateReq.InstanceName, revalidateReq.Uris, revalidateReq.Qualifiers, func(ctx context.Context) error {
+				response, err := cf.fetcher.FetchDirectory(ctx, revalidateReq)
+				if err != nil {
+					return err
+				}
+				if response.Status.GetCode() != int32(codes.OK) {
+					return status.ErrorProto(response.Status)
+				}
+				return cf.putAsset(ctx, instanceName, revalidateReq.Uris, response.Uri, response.Qualifiers, response.RootDirectoryDigest)
+			})
+		}
 		return &remoteasset.FetchDirectoryResponse{
 			Status:              status.New(codes.OK, "Directory fetched successfully from asset cache").Proto(),
 			Uri:                 uri,
@@ -156,30 +198,68 @@ func (cf *cachingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.F
 	if err != nil {
 		return nil, err
 	}
+	if response.Status.Code != 0 {
+		return response, nil
+	}
 
-	// Cache fetched blob with single URI
-	assetRef := storage.NewAssetReference([]string{response.Uri}, response.Qualifiers)
-	assetData := storage.NewAsset(response.RootDirectoryDigest, getDefaultTimestamp())
-	err = cf.assetStore.Put(ctx, assetRef, assetData, instanceName)
-	if err != nil {
+	if err := cf.putAsset(ctx, instanceName, req.Uris, response.Uri, response.Qualifiers, response.RootDirectoryDigest); err != nil {
 		return response, err
 	}
-	if len(req.Uris) > 1 {
-		// Cache fetched blob with list of URIs
-		assetRef = storage.NewAssetReference(req.Uris, assetRef.Qualifiers)
-		err = cf.assetStore.Put(ctx, assetRef, assetData, instanceName)
-		if err != nil {
-			return response, err
+	return response, nil
+}
+
+// putAsset stores a fetched asset in the asset cache, both under the
+// URI from which it was fetched and under the full list of URIs of the
+// request.
+func (cf *cachingFetcher) putAsset(ctx context.Context, instanceName bb_digest.InstanceName, requestURIs []string, uri string, qualifiers []*remoteasset.Qualifier, digest *remoteexecution.Digest) error {
+	// Cache fetched asset with single URI
+	assetRef := storage.NewAssetReference([]string{uri}, qualifiers)
+	assetData, err := cf.freshnessPolicy.NewAsset(instanceName, digest, nil)
+	if err != nil {
+		return err
+	}
+	assetData.OriginUri = uri
+	if err := cf.assetStore.Put(ctx, assetRef, assetData, instanceName); err != nil {
+		return err
+	}
+	if len(requestURIs) > 1 {
+		// Cache fetched asset with list of URIs
+		assetRef = storage.NewAssetReference(requestURIs, assetRef.Qualifiers)
+		if err := cf.assetStore.Put(ctx, assetRef, assetData, instanceName); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	return response, nil
+// revalidate fetches a stale asset again in the background, unless it
+// is already being fetched again. The fetch is detached from the
+// context of the caller, as the caller has already been provided with
+// the stale asset.
+func (cf *cachingFetcher) revalidate(ctx context.Context, operation, instanceName string, uris []string, qualifiers []*remoteasset.Qualifier, fetch func(ctx context.Context) error) {
+	key, err := getRequestKey(operation, instanceName, uris, qualifiers)
+	if err != nil {
+		log.Printf("Failed to revalidate asset %s: %v", uris, err)
+		return
+	}
+	cf.revalidationsLock.Lock()
+	if _, ok := cf.revalidations[key]; ok {
+		cf.revalidationsLock.Unlock()
+		return
+	}
+	cf.revalidations[key] = struct{}{}
+	cf.revalidationsLock.Unlock()
+
+	go func() {
+		if err := fetch(context.WithoutCancel(ctx)); err != nil {
+			log.Printf("Failed to revalidate asset %s: %v", uris, err)
+		}
+		cf.revalidationsLock.Lock()
+		delete(cf.revalidations, key)
+		cf.revalidationsLock.Unlock()
+	}()
 }
 
 func (cf *cachingFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
 	return cf.fetcher.CheckQualifiers(qualifiers)
 }
-
-func getDefaultTimestamp() *timestamppb.Timestamp {
-	return timestamppb.New(time.Unix(0, 0))
-}
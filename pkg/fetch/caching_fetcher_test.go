@@ -12,8 +12,10 @@ import (
 	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	protostatus "google.golang.org/genproto/googleapis/rpc/status"
@@ -41,7 +43,7 @@ func TestFetchBlobCaching(t *testing.T) {
 	backend := mock.NewMockBlobAccess(ctrl)
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
 	mockFetcher := mock.NewMockFetcher(ctrl)
-	cachingFetcher := fetch.NewCachingFetcher(mockFetcher, assetStore)
+	cachingFetcher := fetch.NewCachingFetcher(mockFetcher, assetStore, fetch.NoCompletenessChecking, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	t.Run("Success", func(t *testing.T) {
 		backendGetCall := backend.EXPECT().Get(ctx, refDigest).Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Blob not found")))
@@ -96,7 +98,7 @@ func TestFetchDirectoryCaching(t *testing.T) {
 	backend := mock.NewMockBlobAccess(ctrl)
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
 	mockFetcher := mock.NewMockFetcher(ctrl)
-	cachingFetcher := fetch.NewCachingFetcher(mockFetcher, assetStore)
+	cachingFetcher := fetch.NewCachingFetcher(mockFetcher, assetStore, fetch.NoCompletenessChecking, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	t.Run("Success", func(t *testing.T) {
 		backendGetCall := backend.EXPECT().Get(ctx, refDigest).Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Directory not found")))
@@ -162,7 +164,7 @@ func TestCachingFetcherExpiry(t *testing.T) {
 		Code:    5,
 		Message: "Not found",
 	})
-	cacheFetcher := fetch.NewCachingFetcher(baseFetcher, assetStore)
+	cacheFetcher := fetch.NewCachingFetcher(baseFetcher, assetStore, fetch.NoCompletenessChecking, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	_, err = cacheFetcher.FetchBlob(ctx, request)
 	require.Equal(t, status.ErrorProto(&protostatus.Status{Code: 5, Message: "Not found"}), err)
@@ -198,8 +200,286 @@ func TestCachingFetcherOldestContentAccepted(t *testing.T) {
 		Code:    5,
 		Message: "Not found",
 	})
-	cacheFetcher := fetch.NewCachingFetcher(baseFetcher, assetStore)
+	cacheFetcher := fetch.NewCachingFetcher(baseFetcher, assetStore, fetch.NoCompletenessChecking, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	_, err = cacheFetcher.FetchBlob(ctx, request)
 	require.Equal(t, status.ErrorProto(&protostatus.Status{Code: 5, Message: "Not found"}), err)
 }
+
+func TestCachingFetcherFreshnessPolicy(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/file.txt"
+	request := &remoteasset.FetchBlobRequest{
+		InstanceName: "instance",
+		Uris:         []string{uri},
+	}
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	oldDigest := &remoteexecution.Digest{Hash: "ad84ffc44bab3f84fc3396b4678c1fd39770fa373c3f14eedc5d60e648067960", SizeBytes: 234}
+	newDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	assetRef := storage.NewAssetReference([]string{uri}, nil)
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	cachingFetcher := fetch.NewCachingFetcher(
+		baseFetcher,
+		assetStore,
+		fetch.NoCompletenessChecking,
+		storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{}, map[bb_digest.InstanceName]storage.FreshnessParameters{
+			instanceName: {DefaultTTL: time.Hour},
+		}))
+
+	t.Run("NotExpired", func(t *testing.T) {
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(&asset.Asset{
+			Digest:      oldDigest,
+			ExpireAt:    timestamppb.New(time.Unix(1000, 0)),
+			LastUpdated: timestamppb.New(time.Unix(500, 0)),
+		}, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(999, 0))
+
+		response, err := cachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, oldDigest, response.BlobDigest)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// Expiration should be evaluated against the clock of the
+		// policy. Newly fetched assets should receive the default
+		// TTL of the instance name.
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(&asset.Asset{
+			Digest:      oldDigest,
+			ExpireAt:    timestamppb.New(time.Unix(1000, 0)),
+			LastUpdated: timestamppb.New(time.Unix(500, 0)),
+		}, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(1001, 0))
+		response := &remoteasset.FetchBlobResponse{
+			Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+			Uri:        uri,
+			BlobDigest: newDigest,
+		}
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(response, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(1002, 0))
+		assetStore.EXPECT().Put(ctx, assetRef, testutil.EqProto(t, &asset.Asset{
+			Digest:      newDigest,
+			ExpireAt:    timestamppb.New(time.Unix(4602, 0)),
+			LastUpdated: timestamppb.New(time.Unix(1002, 0)),
+			OriginUri:   uri,
+		}), instanceName)
+
+		r, err := cachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+	})
+}
+
+func TestCachingFetcherStaleWhileRevalidate(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/file.txt"
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	oldDigest := &remoteexecution.Digest{Hash: "ad84ffc44bab3f84fc3396b4678c1fd39770fa373c3f14eedc5d60e648067960", SizeBytes: 234}
+	newDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	assetRef := storage.NewAssetReference([]string{uri}, nil)
+	staleAsset := &asset.Asset{
+		Digest:      oldDigest,
+		ExpireAt:    timestamppb.New(time.Unix(1000, 0)),
+		LastUpdated: timestamppb.New(time.Unix(500, 0)),
+	}
+	newAsset := &asset.Asset{
+		Digest:      newDigest,
+		ExpireAt:    timestamppb.New(time.Unix(8300, 0)),
+		LastUpdated: timestamppb.New(time.Unix(4700, 0)),
+		OriginUri:   uri,
+	}
+	fetchedResponse := &remoteasset.FetchBlobResponse{
+		Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+		Uri:        uri,
+		BlobDigest: newDigest,
+	}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	cachingFetcher := fetch.NewCachingFetcher(
+		baseFetcher,
+		assetStore,
+		fetch.NoCompletenessChecking,
+		storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{}, map[bb_digest.InstanceName]storage.FreshnessParameters{
+			instanceName: {
+				DefaultTTL:           time.Hour,
+				StaleWhileRevalidate: time.Hour,
+			},
+		}))
+
+	t.Run("Stale", func(t *testing.T) {
+		// The stale asset should be returned immediately, while
+		// the asset is fetched again in the background.
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		}
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(staleAsset, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(1500, 0))
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), testutil.EqProto(t, request)).Return(fetchedResponse, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(4700, 0))
+		revalidated := make(chan struct{})
+		assetStore.EXPECT().Put(gomock.Any(), assetRef, testutil.EqProto(t, newAsset), instanceName).
+			DoAndReturn(func(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance bb_digest.InstanceName) error {
+				close(revalidated)
+				return nil
+			})
+
+		response, err := cachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, oldDigest, response.BlobDigest)
+		<-revalidated
+	})
+
+	t.Run("OldestContentAccepted", func(t *testing.T) {
+		// Stale assets that are older than the oldest content
+		// accepted by the request must not be returned. The
+		// asset should be fetched synchronously instead.
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName:          "instance",
+			Uris:                  []string{uri},
+			OldestContentAccepted: timestamppb.New(time.Unix(600, 0)),
+		}
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(staleAsset, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(1500, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(fetchedResponse, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(4700, 0))
+		assetStore.EXPECT().Put(ctx, assetRef, testutil.EqProto(t, newAsset), instanceName)
+
+		response, err := cachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, fetchedResponse, response)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		// Assets that have been expired for longer than the
+		// stale-while-revalidate duration should be fetched
+		// synchronously.
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		}
+		assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(staleAsset, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(4601, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(fetchedResponse, nil)
+		mockClock.EXPECT().Now().Return(time.Unix(4700, 0))
+		assetStore.EXPECT().Put(ctx, assetRef, testutil.EqProto(t, newAsset), instanceName)
+
+		response, err := cachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, fetchedResponse, response)
+	})
+}
+
+// notifyingAssetStore is an AssetStore that reports the completion of
+// every call to Put, so that tests can wait for revalidations in the
+// background to finish.
+type notifyingAssetStore struct {
+	storage.AssetStore
+	puts chan<- struct{}
+}
+
+func (as *notifyingAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance bb_digest.InstanceName) error {
+	err := as.AssetStore.Put(ctx, ref, data, instance)
+	as.puts <- struct{}{}
+	return err
+}
+
+func TestCachingFetcherStaleWhileRevalidateMemoryAssetStore(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/file.txt"
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	oldDigest := &remoteexecution.Digest{Hash: "ad84ffc44bab3f84fc3396b4678c1fd39770fa373c3f14eedc5d60e648067960", SizeBytes: 234}
+	newDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	request := &remoteasset.FetchBlobRequest{
+		InstanceName: "instance",
+		Uris:         []string{uri},
+	}
+
+	// Expired assets should be served from the memory asset store
+	// while they are stale, as opposed to being discarded.
+	storeClock := mock.NewMockClock(ctrl)
+	storeClock.EXPECT().Now().Return(time.Unix(1500, 0)).AnyTimes()
+	puts := make(chan struct{}, 1)
+	assetStore := &notifyingAssetStore{
+		AssetStore: storage.NewMemoryAssetStore(10, 1024*1024, storeClock, time.Hour),
+		puts:       puts,
+	}
+	require.NoError(t, assetStore.Put(ctx, storage.NewAssetReference([]string{uri}, nil), &asset.Asset{
+		Digest:      oldDigest,
+		ExpireAt:    timestamppb.New(time.Unix(1000, 0)),
+		LastUpdated: timestamppb.New(time.Unix(500, 0)),
+	}, instanceName))
+	<-puts
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	cachingFetcher := fetch.NewCachingFetcher(
+		baseFetcher,
+		assetStore,
+		fetch.NoCompletenessChecking,
+		storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{}, map[bb_digest.InstanceName]storage.FreshnessParameters{
+			instanceName: {
+				DefaultTTL:           time.Hour,
+				StaleWhileRevalidate: time.Hour,
+			},
+		}))
+
+	mockClock.EXPECT().Now().Return(time.Unix(1500, 0))
+	baseFetcher.EXPECT().FetchBlob(gomock.Any(), testutil.EqProto(t, request)).Return(&remoteasset.FetchBlobResponse{
+		Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+		Uri:        uri,
+		BlobDigest: newDigest,
+	}, nil)
+	mockClock.EXPECT().Now().Return(time.Unix(1500, 0))
+	response, err := cachingFetcher.FetchBlob(ctx, request)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, oldDigest, response.BlobDigest)
+
+	// Once revalidated, the new asset should be returned.
+	<-puts
+	mockClock.EXPECT().Now().Return(time.Unix(1600, 0))
+	response, err = cachingFetcher.FetchBlob(ctx, request)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, newDigest, response.BlobDigest)
+}
+
+func TestCachingFetcherFetchDirectoryTimeout(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/repository.git"
+	request := &remoteasset.FetchDirectoryRequest{
+		InstanceName: "instance",
+		Uris:         []string{uri},
+	}
+	instanceName := bb_digest.MustNewInstanceName("instance")
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	cachingFetcher := fetch.NewCachingFetcher(
+		fetch.NewTimeoutFetcher(baseFetcher, mockClock, time.Minute, time.Hour),
+		assetStore,
+		fetch.NoCompletenessChecking,
+		storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{}, nil))
+
+	// Fetches that time out are reported through the status of the
+	// response. Such responses don't contain a digest, and must not
+	// be stored in the asset cache.
+	assetStore.EXPECT().Get(ctx, storage.NewAssetReference([]string{uri}, nil), instanceName).
+		Return(nil, status.Error(codes.NotFound, "Asset not found"))
+	expiredCtx, cancel := context.WithDeadline(ctx, time.Unix(0, 0))
+	defer cancel()
+	mockClock.EXPECT().NewContextWithTimeout(ctx, time.Minute).Return(expiredCtx, cancel)
+	baseFetcher.EXPECT().FetchDirectory(expiredCtx, request).Return(nil, status.Error(codes.Unavailable, "Connection reset"))
+
+	response, err := cachingFetcher.FetchDirectory(ctx, request)
+	require.NoError(t, err)
+	require.Equal(t, int32(codes.DeadlineExceeded), response.Status.Code)
+}
@@ -0,0 +1,145 @@
+package fetch
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+	"strings"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// sriAlgorithms contains the hash algorithms that may be used in
+// Subresource Integrity values, keyed by their SRI prefix.
+var sriAlgorithms = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha384": sha512.New384,
+	"sha512": sha512.New,
+}
+
+// sriChecksum is a single hash expression contained in a Subresource
+// Integrity value, such as "sha384-<base64>".
+type sriChecksum struct {
+	algorithm string
+	hash      []byte
+}
+
+// sriChecksums is the list of hash expressions contained in a
+// Subresource Integrity value. Content is considered valid if it
+// matches any of the hash expressions.
+type sriChecksums []sriChecksum
+
+// getChecksumSri extracts the list of hash expressions from the
+// 'checksum.sri' qualifier, if present.
+func getChecksumSri(qualifiers []*remoteasset.Qualifier) (sriChecksums, error) {
+	for _, qualifier := range qualifiers {
+		if qualifier.Name == "checksum.sri" {
+			return parseChecksumSri(qualifier.Value)
+		}
+	}
+	return nil, nil
+}
+
+// parseChecksumSri parses a Subresource Integrity value, which consists
+// of one or more whitespace separated hash expressions. As required by
+// the specification, hash expressions using unknown algorithms are
+// ignored, as are any options following the base64 encoded hash.
+func parseChecksumSri(value string) (sriChecksums, error) {
+	var checksums sriChecksums
+	for _, expression := range strings.Fields(value) {
+		algorithm, b64hash, ok := strings.Cut(expression, "-")
+		if !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Malformed checksum %#v: expected format <algorithm>-<base64 hash>", expression)
+		}
+		newHash, ok := sriAlgorithms[algorithm]
+		if !ok {
+			continue
+		}
+		b64hash, _, _ = strings.Cut(b64hash, "?")
+		decoded, err := base64.StdEncoding.DecodeString(b64hash)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "Failed to decode checksum as b64 encoded %s sum: %s", algorithm, err.Error())
+		}
+		if len(decoded) != newHash().Size() {
+			return nil, status.Errorf(codes.InvalidArgument, "Checksum %#v has length %d, while %s sums have length %d", expression, len(decoded), algorithm, newHash().Size())
+		}
+		checksums = append(checksums, sriChecksum{
+			algorithm: algorithm,
+			hash:      decoded,
+		})
+	}
+	if value != "" && len(checksums) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "Checksum %#v does not contain any supported hash algorithms", value)
+	}
+	return checksums, nil
+}
+
+// getSingleSha256 returns the SHA-256 hash of the content, if it is
+// the only hash expression in the list. In that case the content can
+// be validated against the hash directly.
+func (c sriChecksums) getSingleSha256() ([]byte, bool) {
+	if len(c) != 1 || c[0].algorithm != "sha256" {
+		return nil, false
+	}
+	return c[0].hash, true
+}
+
+// newVerifier creates an io.Writer that computes the hashes needed to
+// verify content against the list of hash expressions.
+func (c sriChecksums) newVerifier() *sriVerifier {
+	hashers := map[string]hash.Hash{}
+	for _, checksum := range c {
+		if _, ok := hashers[checksum.algorithm]; !ok {
+			hashers[checksum.algorithm] = sriAlgorithms[checksum.algorithm]()
+		}
+	}
+	return &sriVerifier{
+		checksums: c,
+		hashers:   hashers,
+	}
+}
+
+// sriVerifier is an io.Writer that verifies the data written into it
+// against a list of Subresource Integrity hash expressions.
+type sriVerifier struct {
+	checksums sriChecksums
+	hashers   map[string]hash.Hash
+}
+
+func (v *sriVerifier) Write(p []byte) (int, error) {
+	for _, hasher := range v.hashers {
+		hasher.Write(p)
+	}
+	return len(p), nil
+}
+
+// Verify returns an error if the data written into the verifier does
+// not match any of the hash expressions.
+func (v *sriVerifier) Verify() error {
+	if len(v.checksums) == 0 {
+		return nil
+	}
+	sums := make(map[string][]byte, len(v.hashers))
+	for algorithm, hasher := range v.hashers {
+		sums[algorithm] = hasher.Sum(nil)
+	}
+	for _, checksum := range v.checksums {
+		if bytes.Equal(sums[checksum.algorithm], checksum.hash) {
+			return nil
+		}
+	}
+	got := make([]string, 0, len(sums))
+	for _, checksum := range v.checksums {
+		if sum, ok := sums[checksum.algorithm]; ok {
+			got = append(got, checksum.algorithm+"-"+base64.StdEncoding.EncodeToString(sum))
+			delete(sums, checksum.algorithm)
+		}
+	}
+	return status.Errorf(codes.NotFound, "Response body has checksum %#v, which does not match any of the expected checksums", strings.Join(got, " "))
+}
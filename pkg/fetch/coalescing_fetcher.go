@@ -0,0 +1,169 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// inFlightFetch is a fetch that is shared by one or more callers.
+type inFlightFetch struct {
+	waiters int
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	response proto.Message
+	err      error
+}
+
+type coalescingFetcher struct {
+	fetcher Fetcher
+
+	lock     sync.Mutex
+	inFlight map[string]*inFlightFetch
+}
+
+// NewCoalescingFetcher creates a decorator for Fetcher that merges
+// concurrent requests for the same asset, so that the backend only
+// fetches it once. Requests are considered identical if they have the
+// same instance name, URIs and qualifiers, regardless of their order,
+// and the same oldest_content_accepted and timeout.
+//
+// Every caller may abandon the request independently. The shared
+// fetch is only cancelled once all callers have abandoned it.
+func NewCoalescingFetcher(fetcher Fetcher) Fetcher {
+	return &coalescingFetcher{
+		fetcher:  fetcher,
+		inFlight: map[string]*inFlightFetch{},
+	}
+}
+
+// getRequestKey computes a key that uniquely identifies the asset
+// requested, regardless of the order of the URIs and qualifiers.
+func getRequestKey(operation, instanceName string, uris []string, qualifiers []*remoteasset.Qualifier) (string, error) {
+	// NewAssetReference() sorts its arguments in place. Copy them to
+	// prevent the request from being modified.
+	assetRef := storage.NewAssetReference(
+		append([]string(nil), uris...),
+		append([]*remoteasset.Qualifier(nil), qualifiers...))
+	assetRefPb, err := proto.MarshalOptions{Deterministic: true}.Marshal(assetRef)
+	if err != nil {
+		return "", util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal asset reference")
+	}
+	return operation + "\x00" + instanceName + "\x00" + string(assetRefPb), nil
+}
+
+// getCoalescingKey extends the key of a request with the properties
+// of the request that affect its outcome, so that requests are only
+// merged if the shared fetch is valid for all of them. Otherwise, a
+// request requiring fresh content could be provided with a response
+// served from the asset cache, or a request could be subjected to the
+// shorter timeout of another request.
+func getCoalescingKey(requestKey string, oldestContentAccepted *timestamppb.Timestamp, timeout *durationpb.Duration) string {
+	return fmt.Sprintf(
+		"%s\x00%d.%09d\x00%d.%09d",
+		requestKey,
+		oldestContentAccepted.GetSeconds(), oldestContentAccepted.GetNanos(),
+		timeout.GetSeconds(), timeout.GetNanos())
+}
+
+// do runs a fetch, or joins an identical fetch that is already in
+// progress. The response returned by this function is shared between
+// callers, and must not be modified.
+func (cf *coalescingFetcher) do(ctx context.Context, key string, fetch func(ctx context.Context) (proto.Message, error)) (proto.Message, error) {
+	cf.lock.Lock()
+	f, ok := cf.inFlight[key]
+	if !ok {
+		// Start a new fetch. It is detached from the context of
+		// the caller, so that it keeps on running if this caller
+		// goes away while others are still waiting.
+		fetchCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &inFlightFetch{
+			cancel: cancel,
+			done:   make(chan struct{}),
+		}
+		cf.inFlight[key] = f
+		go func() {
+			response, err := fetch(fetchCtx)
+			cf.lock.Lock()
+			if cf.inFlight[key] == f {
+				delete(cf.inFlight, key)
+			}
+			f.response, f.err = response, err
+			cf.lock.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	cf.lock.Unlock()
+
+	select {
+	case <-f.done:
+		return f.response, f.err
+	case <-ctx.Done():
+		cf.lock.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// All callers have gone. Abort the fetch, and make
+			// sure that subsequent callers start a new one.
+			f.cancel()
+			if cf.inFlight[key] == f {
+				delete(cf.inFlight, key)
+			}
+		}
+		cf.lock.Unlock()
+		return nil, util.StatusFromContext(ctx)
+	}
+}
+
+func (cf *coalescingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+	key, err := getRequestKey("FetchBlob", req.InstanceName, req.Uris, req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+	key = getCoalescingKey(key, req.OldestContentAccepted, req.Timeout)
+	response, err := cf.do(ctx, key, func(ctx context.Context) (proto.Message, error) {
+		response, err := cf.fetcher.FetchBlob(ctx, req)
+		if response == nil {
+			return nil, err
+		}
+		return response, err
+	})
+	if response == nil {
+		return nil, err
+	}
+	return proto.Clone(response).(*remoteasset.FetchBlobResponse), err
+}
+
+func (cf *coalescingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	key, err := getRequestKey("FetchDirectory", req.InstanceName, req.Uris, req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+	key = getCoalescingKey(key, req.OldestContentAccepted, req.Timeout)
+	response, err := cf.do(ctx, key, func(ctx context.Context) (proto.Message, error) {
+		response, err := cf.fetcher.FetchDirectory(ctx, req)
+		if response == nil {
+			return nil, err
+		}
+		return response, err
+	})
+	if response == nil {
+		return nil, err
+	}
+	return proto.Clone(response).(*remoteasset.FetchDirectoryResponse), err
+}
+
+func (cf *coalescingFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
+	return cf.fetcher.CheckQualifiers(qualifiers)
+}
@@ -0,0 +1,180 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestCoalescingFetcherFetchBlob(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	coalescingFetcher := fetch.NewCoalescingFetcher(baseFetcher)
+	response := &remoteasset.FetchBlobResponse{
+		Status: status.New(codes.OK, "Blob fetched successfully!").Proto(),
+		Uri:    "https://example.com/a.tar.gz",
+	}
+
+	t.Run("Shared", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+				close(started)
+				<-release
+				require.NoError(t, ctx.Err())
+				return response, nil
+			})
+
+		results := make(chan *remoteasset.FetchBlobResponse, 1)
+		go func() {
+			r, err := coalescingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+				InstanceName: "instance",
+				Uris:         []string{"https://example.com/a.tar.gz", "https://mirror.example.com/a.tar.gz"},
+				Qualifiers: []*remoteasset.Qualifier{
+					{Name: "checksum.sri", Value: "sha256-abc"},
+					{Name: "bazel.canonical_id", Value: "a"},
+				},
+			})
+			require.NoError(t, err)
+			results <- r
+		}()
+		<-started
+
+		// A request that only differs in the order of its URIs and
+		// qualifiers should join the fetch that is in progress,
+		// instead of calling into the backend. Abandoning it should
+		// not cause the fetch to be cancelled, as the first caller
+		// is still waiting.
+		ctx2, cancel2 := context.WithCancel(ctx)
+		cancel2()
+		_, err := coalescingFetcher.FetchBlob(ctx2, &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://mirror.example.com/a.tar.gz", "https://example.com/a.tar.gz"},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.canonical_id", Value: "a"},
+				{Name: "checksum.sri", Value: "sha256-abc"},
+			},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+
+		close(release)
+		testutil.RequireEqualProto(t, response, <-results)
+	})
+
+	t.Run("DifferentInstanceName", func(t *testing.T) {
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), gomock.Any()).Return(response, nil).Times(2)
+
+		for _, instanceName := range []string{"a", "b"} {
+			r, err := coalescingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+				InstanceName: instanceName,
+				Uris:         []string{"https://example.com/a.tar.gz"},
+			})
+			require.NoError(t, err)
+			testutil.RequireEqualProto(t, response, r)
+		}
+	})
+
+	t.Run("DifferentFreshnessRequirements", func(t *testing.T) {
+		// Requests that differ in the age of the content they
+		// accept or in their timeout should not be merged, as the
+		// response to one of them may not be valid for the other.
+		started := make(chan struct{})
+		release := make(chan struct{})
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+				close(started)
+				<-release
+				return response, nil
+			})
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/c.tar.gz"},
+		}
+
+		results := make(chan *remoteasset.FetchBlobResponse, 1)
+		go func() {
+			r, err := coalescingFetcher.FetchBlob(ctx, request)
+			require.NoError(t, err)
+			results <- r
+		}()
+		<-started
+
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), gomock.Any()).Return(response, nil).Times(2)
+		r, err := coalescingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName:          "instance",
+			Uris:                  []string{"https://example.com/c.tar.gz"},
+			OldestContentAccepted: timestamppb.New(time.Unix(1000, 0)),
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+		r, err = coalescingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/c.tar.gz"},
+			Timeout:      durationpb.New(time.Second),
+		})
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+
+		close(release)
+		testutil.RequireEqualProto(t, response, <-results)
+	})
+
+	t.Run("AllCallersGone", func(t *testing.T) {
+		started := make(chan struct{})
+		baseFetcher.EXPECT().FetchBlob(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+				close(started)
+				<-ctx.Done()
+				return nil, status.Error(codes.Canceled, "context canceled")
+			})
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/b.tar.gz"},
+		}
+
+		ctx1, cancel1 := context.WithCancel(ctx)
+		errs := make(chan error, 1)
+		go func() {
+			_, err := coalescingFetcher.FetchBlob(ctx1, request)
+			errs <- err
+		}()
+		<-started
+
+		ctx2, cancel2 := context.WithCancel(ctx)
+		cancel2()
+		_, err := coalescingFetcher.FetchBlob(ctx2, request)
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), err)
+
+		// Once the last caller leaves, the fetch is aborted.
+		cancel1()
+		testutil.RequireEqualStatus(t, status.Error(codes.Canceled, "context canceled"), <-errs)
+	})
+}
+
+func TestCoalescingFetcherFetchDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	coalescingFetcher := fetch.NewCoalescingFetcher(baseFetcher)
+
+	baseFetcher.EXPECT().FetchDirectory(gomock.Any(), gomock.Any()).Return(nil, status.Error(codes.NotFound, "Not found"))
+
+	_, err := coalescingFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+		InstanceName: "instance",
+		Uris:         []string{"https://example.com/a.tar.gz"},
+	})
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Not found"), err)
+}
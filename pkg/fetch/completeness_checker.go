@@ -0,0 +1,278 @@
+package fetch
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// CompletenessChecker is used by CachingFetcher to determine whether
+// the objects referenced by a cached asset are still present in the
+// Content Addressable Storage (CAS). If an object is absent, a
+// NOT_FOUND error is returned, causing the asset to be fetched again.
+type CompletenessChecker interface {
+	CheckBlob(ctx context.Context, instanceName bb_digest.InstanceName, blobDigest *remoteexecution.Digest) error
+	CheckDirectory(ctx context.Context, instanceName bb_digest.InstanceName, rootDirectoryDigest *remoteexecution.Digest) error
+}
+
+type noCompletenessChecker struct{}
+
+func (noCompletenessChecker) CheckBlob(ctx context.Context, instanceName bb_digest.InstanceName, blobDigest *remoteexecution.Digest) error {
+	return nil
+}
+
+func (noCompletenessChecker) CheckDirectory(ctx context.Context, instanceName bb_digest.InstanceName, rootDirectoryDigest *remoteexecution.Digest) error {
+	return nil
+}
+
+// NoCompletenessChecking is a CompletenessChecker that assumes that
+// all objects referenced by cached assets are present in the CAS.
+var NoCompletenessChecking CompletenessChecker = noCompletenessChecker{}
+
+type casCompletenessChecker struct {
+	contentAddressableStorage blobstore.BlobAccess
+	batchSize                 int
+	maximumMessageSizeBytes   int
+	clock                     clock.Clock
+	refreshInterval           time.Duration
+
+	lock sync.Mutex
+	// Objects that were recently found to be present.
+	refreshedDigests map[bb_digest.Digest]*list.Element
+	// The same objects, ordered by the time at which they need to be
+	// checked again.
+	refreshQueue list.List
+}
+
+// refreshedDigest is an object that was recently found to be present.
+type refreshedDigest struct {
+	digest    bb_digest.Digest
+	refreshAt time.Time
+}
+
+// NewCASCompletenessChecker creates a CompletenessChecker that calls
+// FindMissing() against the CAS for the objects referenced by cached
+// assets. For directories, all Directory messages contained in the
+// tree are loaded to obtain the digests of the files contained in it.
+//
+// The use of this type is required when the asset cache and the CAS
+// are two separate data stores that don't share a common garbage
+// collection scheme, which is for example the case when the CAS is
+// backed by local storage. As most storage backends refresh objects
+// for which FindMissing() is called, it also prevents assets that are
+// in frequent use from being evicted from the CAS.
+//
+// Objects that were found to be present are not checked again until
+// refreshInterval has passed, preventing frequently requested assets
+// from causing excessive load on the CAS.
+func NewCASCompletenessChecker(contentAddressableStorage blobstore.BlobAccess, batchSize, maximumMessageSizeBytes int, clock clock.Clock, refreshInterval time.Duration) CompletenessChecker {
+	return &casCompletenessChecker{
+		contentAddressableStorage: contentAddressableStorage,
+		batchSize:                 batchSize,
+		maximumMessageSizeBytes:   maximumMessageSizeBytes,
+		clock:                     clock,
+		refreshInterval:           refreshInterval,
+
+		refreshedDigests: map[bb_digest.Digest]*list.Element{},
+	}
+}
+
+// wasRecentlyRefreshed returns whether an object was found to be
+// present less than refreshInterval ago.
+func (cc *casCompletenessChecker) wasRecentlyRefreshed(blobDigest bb_digest.Digest, now time.Time) bool {
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+	element, ok := cc.refreshedDigests[blobDigest]
+	return ok && now.Before(element.Value.(*refreshedDigest).refreshAt)
+}
+
+// markRefreshed records that objects were found to be present, so that
+// they are not checked again until refreshInterval has passed.
+func (cc *casCompletenessChecker) markRefreshed(blobDigests []bb_digest.Digest, now time.Time) {
+	if cc.refreshInterval <= 0 {
+		return
+	}
+
+	cc.lock.Lock()
+	defer cc.lock.Unlock()
+
+	// Forget about objects that need to be checked again.
+	for element := cc.refreshQueue.Front(); element != nil; element = cc.refreshQueue.Front() {
+		entry := element.Value.(*refreshedDigest)
+		if now.Before(entry.refreshAt) {
+			break
+		}
+		cc.refreshQueue.Remove(element)
+		delete(cc.refreshedDigests, entry.digest)
+	}
+
+	refreshAt := now.Add(cc.refreshInterval)
+	for _, blobDigest := range blobDigests {
+		if element, ok := cc.refreshedDigests[blobDigest]; ok {
+			cc.refreshQueue.Remove(element)
+		}
+		cc.refreshedDigests[blobDigest] = cc.refreshQueue.PushBack(&refreshedDigest{
+			digest:    blobDigest,
+			refreshAt: refreshAt,
+		})
+	}
+}
+
+// findMissingQueue is a helper for calling BlobAccess.FindMissing() in
+// batches, as opposed to calling it for individual digests.
+type findMissingQueue struct {
+	context                   context.Context
+	digestFunction            bb_digest.Function
+	contentAddressableStorage blobstore.BlobAccess
+	batchSize                 int
+
+	pending bb_digest.SetBuilder
+}
+
+// deriveDigest converts a digest referenced by a cached asset to an
+// in-memory representation. Malformed digests are reported as
+// NOT_FOUND, causing the asset to be fetched again.
+func (q *findMissingQueue) deriveDigest(blobDigest *remoteexecution.Digest) (bb_digest.Digest, error) {
+	derivedDigest, err := q.digestFunction.NewDigestFromProto(blobDigest)
+	if err != nil {
+		return bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.NotFound, "Asset contained malformed digest")
+	}
+	return derivedDigest, nil
+}
+
+// add a digest to the list of digests that are pending to be checked
+// for existence in the CAS.
+func (q *findMissingQueue) add(blobDigest bb_digest.Digest) error {
+	if q.pending.Length() >= q.batchSize {
+		if err := q.finalize(); err != nil {
+			return err
+		}
+		q.pending = bb_digest.NewSetBuilder()
+	}
+	q.pending.Add(blobDigest)
+	return nil
+}
+
+// finalize by checking the last batch of digests for existence.
+func (q *findMissingQueue) finalize() error {
+	if q.pending.Length() == 0 {
+		return nil
+	}
+	missing, err := q.contentAddressableStorage.FindMissing(q.context, q.pending.Build())
+	if err != nil {
+		return util.StatusWrap(err, "Failed to determine existence of referenced objects")
+	}
+	if blobDigest, ok := missing.First(); ok {
+		return status.Errorf(codes.NotFound, "Object %s referenced by the asset is not present in the Content Addressable Storage", blobDigest)
+	}
+	return nil
+}
+
+func (cc *casCompletenessChecker) newFindMissingQueue(ctx context.Context, instanceName bb_digest.InstanceName, rootDigest *remoteexecution.Digest) (*findMissingQueue, bb_digest.Digest, error) {
+	digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(rootDigest.GetHash()))
+	if err != nil {
+		return nil, bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.NotFound, "Asset contained malformed digest")
+	}
+	q := &findMissingQueue{
+		context:                   ctx,
+		digestFunction:            digestFunction,
+		contentAddressableStorage: cc.contentAddressableStorage,
+		batchSize:                 cc.batchSize,
+		pending:                   bb_digest.NewSetBuilder(),
+	}
+	derivedDigest, err := q.deriveDigest(rootDigest)
+	if err != nil {
+		return nil, bb_digest.BadDigest, err
+	}
+	return q, derivedDigest, nil
+}
+
+func (cc *casCompletenessChecker) CheckBlob(ctx context.Context, instanceName bb_digest.InstanceName, blobDigest *remoteexecution.Digest) error {
+	q, derivedDigest, err := cc.newFindMissingQueue(ctx, instanceName, blobDigest)
+	if err != nil {
+		return err
+	}
+	now := cc.clock.Now()
+	if cc.wasRecentlyRefreshed(derivedDigest, now) {
+		return nil
+	}
+	if err := q.add(derivedDigest); err != nil {
+		return err
+	}
+	if err := q.finalize(); err != nil {
+		return err
+	}
+	cc.markRefreshed([]bb_digest.Digest{derivedDigest}, now)
+	return nil
+}
+
+func (cc *casCompletenessChecker) CheckDirectory(ctx context.Context, instanceName bb_digest.InstanceName, rootDirectoryDigest *remoteexecution.Digest) error {
+	q, derivedDigest, err := cc.newFindMissingQueue(ctx, instanceName, rootDirectoryDigest)
+	if err != nil {
+		return err
+	}
+
+	// Load all Directory messages in the tree to obtain the digests
+	// of all files, and call FindMissing() on all of them. Trees of
+	// directories that were recently refreshed are skipped, as
+	// their contents were refreshed along with them.
+	now := cc.clock.Now()
+	var checkedDigests []bb_digest.Digest
+	pendingDirectories := []bb_digest.Digest{derivedDigest}
+	seenDigests := map[bb_digest.Digest]struct{}{derivedDigest: {}}
+	for len(pendingDirectories) > 0 {
+		directoryDigest := pendingDirectories[len(pendingDirectories)-1]
+		pendingDirectories = pendingDirectories[:len(pendingDirectories)-1]
+		if cc.wasRecentlyRefreshed(directoryDigest, now) {
+			continue
+		}
+		if err := q.add(directoryDigest); err != nil {
+			return err
+		}
+		checkedDigests = append(checkedDigests, directoryDigest)
+
+		directoryMessage, err := cc.contentAddressableStorage.Get(ctx, directoryDigest).ToProto(&remoteexecution.Directory{}, cc.maximumMessageSizeBytes)
+		if err != nil {
+			return util.StatusWrapf(err, "Failed to load directory %s", directoryDigest)
+		}
+		directory := directoryMessage.(*remoteexecution.Directory)
+		for _, child := range directory.Files {
+			childDigest, err := q.deriveDigest(child.Digest)
+			if err != nil {
+				return util.StatusWrapf(err, "File %#v in directory %s", child.Name, directoryDigest)
+			}
+			if _, ok := seenDigests[childDigest]; ok || cc.wasRecentlyRefreshed(childDigest, now) {
+				continue
+			}
+			seenDigests[childDigest] = struct{}{}
+			if err := q.add(childDigest); err != nil {
+				return err
+			}
+			checkedDigests = append(checkedDigests, childDigest)
+		}
+		for _, child := range directory.Directories {
+			childDigest, err := q.deriveDigest(child.Digest)
+			if err != nil {
+				return util.StatusWrapf(err, "Directory %#v in directory %s", child.Name, directoryDigest)
+			}
+			if _, ok := seenDigests[childDigest]; !ok {
+				seenDigests[childDigest] = struct{}{}
+				pendingDirectories = append(pendingDirectories, childDigest)
+			}
+		}
+	}
+	if err := q.finalize(); err != nil {
+		return err
+	}
+	cc.markRefreshed(checkedDigests, now)
+	return nil
+}
@@ -0,0 +1,175 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCASCompletenessCheckerCheckBlob(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	completenessChecker := fetch.NewCASCompletenessChecker(contentAddressableStorage, 2, 1024*1024, clock.SystemClock, 0)
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	blobDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", 123)
+
+	t.Run("Present", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().FindMissing(ctx, blobDigest.ToSingletonSet()).Return(bb_digest.EmptySet, nil)
+
+		require.NoError(t, completenessChecker.CheckBlob(ctx, instanceName, blobDigest.GetProto()))
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().FindMissing(ctx, blobDigest.ToSingletonSet()).Return(blobDigest.ToSingletonSet(), nil)
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Object 1-d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db-123-instance referenced by the asset is not present in the Content Addressable Storage"),
+			completenessChecker.CheckBlob(ctx, instanceName, blobDigest.GetProto()))
+	})
+
+	t.Run("MalformedDigest", func(t *testing.T) {
+		require.Equal(t, codes.NotFound, status.Code(completenessChecker.CheckBlob(ctx, instanceName, &remoteexecution.Digest{
+			Hash:      "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db",
+			SizeBytes: -1,
+		})))
+	})
+}
+
+func TestCASCompletenessCheckerCheckDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	completenessChecker := fetch.NewCASCompletenessChecker(contentAddressableStorage, 2, 1024*1024, clock.SystemClock, 0)
+	instanceName := bb_digest.MustNewInstanceName("instance")
+
+	file1 := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "1111111111111111111111111111111111111111111111111111111111111111", 1)
+	file2 := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "2222222222222222222222222222222222222222222222222222222222222222", 2)
+	file3 := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "3333333333333333333333333333333333333333333333333333333333333333", 3)
+	subdirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{Name: "c", Digest: file3.GetProto()},
+		},
+	}
+	subdirectoryDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "4444444444444444444444444444444444444444444444444444444444444444", 4)
+	rootDirectory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{Name: "a", Digest: file1.GetProto()},
+			{Name: "b", Digest: file2.GetProto()},
+		},
+		Directories: []*remoteexecution.DirectoryNode{
+			{Name: "sub1", Digest: subdirectoryDigest.GetProto()},
+			{Name: "sub2", Digest: subdirectoryDigest.GetProto()},
+		},
+	}
+	rootDirectoryDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "5555555555555555555555555555555555555555555555555555555555555555", 5)
+
+	t.Run("Present", func(t *testing.T) {
+		// Identical subdirectories should only be loaded once.
+		// Digests should be checked in batches.
+		contentAddressableStorage.EXPECT().Get(ctx, rootDirectoryDigest).Return(buffer.NewProtoBufferFromProto(rootDirectory, buffer.UserProvided))
+		contentAddressableStorage.EXPECT().Get(ctx, subdirectoryDigest).Return(buffer.NewProtoBufferFromProto(subdirectory, buffer.UserProvided))
+		contentAddressableStorage.EXPECT().FindMissing(ctx, bb_digest.NewSetBuilder().Add(rootDirectoryDigest).Add(file1).Build()).Return(bb_digest.EmptySet, nil)
+		contentAddressableStorage.EXPECT().FindMissing(ctx, bb_digest.NewSetBuilder().Add(file2).Add(subdirectoryDigest).Build()).Return(bb_digest.EmptySet, nil)
+		contentAddressableStorage.EXPECT().FindMissing(ctx, file3.ToSingletonSet()).Return(bb_digest.EmptySet, nil)
+
+		require.NoError(t, completenessChecker.CheckDirectory(ctx, instanceName, rootDirectoryDigest.GetProto()))
+	})
+
+	t.Run("MissingDirectory", func(t *testing.T) {
+		contentAddressableStorage.EXPECT().Get(ctx, rootDirectoryDigest).Return(buffer.NewProtoBufferFromProto(rootDirectory, buffer.UserProvided))
+		contentAddressableStorage.EXPECT().FindMissing(ctx, bb_digest.NewSetBuilder().Add(rootDirectoryDigest).Add(file1).Build()).Return(bb_digest.EmptySet, nil)
+		contentAddressableStorage.EXPECT().Get(ctx, subdirectoryDigest).Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.NotFound, "Failed to load directory 1-4444444444444444444444444444444444444444444444444444444444444444-4-instance: Object not found"),
+			completenessChecker.CheckDirectory(ctx, instanceName, rootDirectoryDigest.GetProto()))
+	})
+}
+
+func TestCASCompletenessCheckerRefreshInterval(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	completenessChecker := fetch.NewCASCompletenessChecker(contentAddressableStorage, 100, 1024*1024, clock, time.Minute)
+	instanceName := bb_digest.MustNewInstanceName("instance")
+
+	fileDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "1111111111111111111111111111111111111111111111111111111111111111", 1)
+	directoryDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "2222222222222222222222222222222222222222222222222222222222222222", 2)
+	directory := &remoteexecution.Directory{
+		Files: []*remoteexecution.FileNode{
+			{Name: "file", Digest: fileDigest.GetProto()},
+		},
+	}
+
+	// The first time the directory is requested, all objects in it
+	// should be refreshed.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	contentAddressableStorage.EXPECT().Get(ctx, directoryDigest).Return(buffer.NewProtoBufferFromProto(directory, buffer.UserProvided))
+	contentAddressableStorage.EXPECT().FindMissing(ctx, bb_digest.NewSetBuilder().Add(directoryDigest).Add(fileDigest).Build()).Return(bb_digest.EmptySet, nil)
+	require.NoError(t, completenessChecker.CheckDirectory(ctx, instanceName, directoryDigest.GetProto()))
+
+	// Requesting the directory or the file contained in it shortly
+	// afterwards should not cause any load on the CAS.
+	clock.EXPECT().Now().Return(time.Unix(1030, 0))
+	require.NoError(t, completenessChecker.CheckDirectory(ctx, instanceName, directoryDigest.GetProto()))
+	clock.EXPECT().Now().Return(time.Unix(1040, 0))
+	require.NoError(t, completenessChecker.CheckBlob(ctx, instanceName, fileDigest.GetProto()))
+
+	// Once the refresh interval has passed, objects should be
+	// refreshed again.
+	clock.EXPECT().Now().Return(time.Unix(1060, 0))
+	contentAddressableStorage.EXPECT().FindMissing(ctx, fileDigest.ToSingletonSet()).Return(bb_digest.EmptySet, nil)
+	require.NoError(t, completenessChecker.CheckBlob(ctx, instanceName, fileDigest.GetProto()))
+}
+
+func TestCachingFetcherCompletenessChecking(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/file.txt"
+	request := &remoteasset.FetchBlobRequest{
+		InstanceName: "instance",
+		Uris:         []string{uri},
+	}
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	blobDigest := bb_digest.MustNewDigest("instance", remoteexecution.DigestFunction_SHA256, "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", 123)
+	assetRef := storage.NewAssetReference([]string{uri}, nil)
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	contentAddressableStorage := mock.NewMockBlobAccess(ctrl)
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	cachingFetcher := fetch.NewCachingFetcher(baseFetcher, assetStore, fetch.NewCASCompletenessChecker(contentAddressableStorage, 100, 1024*1024, clock.SystemClock, 0), storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
+
+	// If the blob referenced by the cached asset has been evicted
+	// from the CAS, the blob should be fetched again.
+	assetStore.EXPECT().Get(ctx, assetRef, instanceName).Return(storage.NewAsset(blobDigest.GetProto(), nil), nil)
+	contentAddressableStorage.EXPECT().FindMissing(ctx, blobDigest.ToSingletonSet()).Return(blobDigest.ToSingletonSet(), nil)
+	response := &remoteasset.FetchBlobResponse{
+		Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+		Uri:        uri,
+		BlobDigest: blobDigest.GetProto(),
+	}
+	baseFetcher.EXPECT().FetchBlob(ctx, request).Return(response, nil)
+	assetStore.EXPECT().Put(ctx, assetRef, gomock.Any(), instanceName)
+
+	r, err := cachingFetcher.FetchBlob(ctx, request)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, response, r)
+}
@@ -0,0 +1,134 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// credentialHelperRequest is the request that is written to the
+// standard input of a credential helper, as described by
+// https://github.com/EngFlow/credential-helper-spec.
+type credentialHelperRequest struct {
+	URI string `json:"uri"`
+}
+
+// credentialHelperResponse is the response that is read from the
+// standard output of a credential helper.
+type credentialHelperResponse struct {
+	Headers map[string][]string `json:"headers"`
+	Expires string              `json:"expires,omitempty"`
+}
+
+type credentialHelperCacheEntry struct {
+	header    http.Header
+	expiresAt time.Time
+}
+
+type credentialHelperHTTPCredentialProvider struct {
+	path          string
+	cacheDuration time.Duration
+	clock         clock.Clock
+
+	lock  sync.Mutex
+	cache map[string]credentialHelperCacheEntry
+}
+
+// NewCredentialHelperHTTPCredentialProvider creates an
+// HTTPCredentialProvider that obtains headers by invoking a credential
+// helper executable, as used by Bazel's --credential_helper flag.
+//
+// The headers returned for a URI are cached for cacheDuration, unless
+// the credential helper reports that they expire sooner.
+func NewCredentialHelperHTTPCredentialProvider(path string, cacheDuration time.Duration, clock clock.Clock) HTTPCredentialProvider {
+	return &credentialHelperHTTPCredentialProvider{
+		path:          path,
+		cacheDuration: cacheDuration,
+		clock:         clock,
+		cache:         map[string]credentialHelperCacheEntry{},
+	}
+}
+
+func (cp *credentialHelperHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	now := cp.clock.Now()
+	cp.lock.Lock()
+	entry, ok := cp.cache[uri]
+	cp.lock.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.header, nil
+	}
+
+	header, expiresAt, err := cp.invoke(ctx, uri)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.IsZero() {
+		expiresAt = now.Add(cp.cacheDuration)
+	}
+
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	for cachedURI, cachedEntry := range cp.cache {
+		if !now.Before(cachedEntry.expiresAt) {
+			delete(cp.cache, cachedURI)
+		}
+	}
+	if now.Before(expiresAt) {
+		cp.cache[uri] = credentialHelperCacheEntry{
+			header:    header,
+			expiresAt: expiresAt,
+		}
+	}
+	return header, nil
+}
+
+// invoke runs the credential helper to obtain the headers for a URI.
+func (cp *credentialHelperHTTPCredentialProvider) invoke(ctx context.Context, uri string) (http.Header, time.Time, error) {
+	request, err := json.Marshal(credentialHelperRequest{URI: uri})
+	if err != nil {
+		return nil, time.Time{}, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal credential helper request")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, cp.path, "get")
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return nil, time.Time{}, status.Errorf(codes.Unauthenticated, "Credential helper %#v failed: %s: %s", cp.path, err, message)
+		}
+		return nil, time.Time{}, status.Errorf(codes.Unauthenticated, "Credential helper %#v failed: %s", cp.path, err)
+	}
+
+	var response credentialHelperResponse
+	if err := json.Unmarshal(stdout.Bytes(), &response); err != nil {
+		return nil, time.Time{}, status.Errorf(codes.Unauthenticated, "Credential helper %#v returned a malformed response: %s", cp.path, err)
+	}
+	var expiresAt time.Time
+	if response.Expires != "" {
+		if expiresAt, err = time.Parse(time.RFC3339, response.Expires); err != nil {
+			return nil, time.Time{}, status.Errorf(codes.Unauthenticated, "Credential helper %#v returned a malformed expiration time: %s", cp.path, err)
+		}
+	}
+	if len(response.Headers) == 0 {
+		// The credential helper has no credentials for this URI.
+		return nil, expiresAt, nil
+	}
+	header := http.Header{}
+	for name, values := range response.Headers {
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+	return header, expiresAt, nil
+}
@@ -0,0 +1,78 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCredentialHelperHTTPCredentialProvider(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	directory := t.TempDir()
+	invocationsPath := filepath.Join(directory, "invocations")
+	helperPath := filepath.Join(directory, "helper")
+	require.NoError(t, os.WriteFile(helperPath, []byte(`#!/bin/sh
+[ "$1" = get ] || exit 1
+cat >> `+invocationsPath+`
+echo >> `+invocationsPath+`
+echo '{"headers": {"Authorization": ["Bearer secret"], "X-Multiple": ["a", "b"]}}'
+`), 0o755))
+	clock := mock.NewMockClock(ctrl)
+	provider := fetch.NewCredentialHelperHTTPCredentialProvider(helperPath, time.Minute, clock)
+
+	t.Run("Cached", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		header, err := provider.GetHeaders(ctx, "https://example.com/file.zip")
+		require.NoError(t, err)
+		require.Equal(t, http.Header{
+			"Authorization": []string{"Bearer secret"},
+			"X-Multiple":    []string{"a", "b"},
+		}, header)
+
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+		header, err = provider.GetHeaders(ctx, "https://example.com/file.zip")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer secret", header.Get("Authorization"))
+
+		invocations, err := os.ReadFile(invocationsPath)
+		require.NoError(t, err)
+		require.Equal(t, "{\"uri\":\"https://example.com/file.zip\"}\n", string(invocations))
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+		_, err := provider.GetHeaders(ctx, "https://example.com/file.zip")
+		require.NoError(t, err)
+
+		invocations, err := os.ReadFile(invocationsPath)
+		require.NoError(t, err)
+		require.Equal(t, "{\"uri\":\"https://example.com/file.zip\"}\n{\"uri\":\"https://example.com/file.zip\"}\n", string(invocations))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		failingHelperPath := filepath.Join(directory, "failing_helper")
+		require.NoError(t, os.WriteFile(failingHelperPath, []byte("#!/bin/sh\necho 'Token expired' >&2\nexit 1\n"), 0o755))
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		credentialProvider := fetch.NewCredentialHelperHTTPCredentialProvider(failingHelperPath, time.Minute, clock)
+		HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: mock.NewMockRoundTripper(ctrl)}, mock.NewMockBlobAccess(ctrl), 1024*1024, "", fetch.NoHTTPRetries, 0, credentialProvider, fetch.HTTPCredentialPrecedenceClient, clock)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{"https://example.com/file.zip"},
+		})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+		require.Contains(t, status.Convert(err).Message(), "Token expired")
+	})
+}
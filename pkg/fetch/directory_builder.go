@@ -0,0 +1,201 @@
+package fetch
+
+import (
+	"context"
+	"path"
+	"sort"
+	"strings"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// directoryBuilder is an in-memory representation of a directory
+// hierarchy, whose files are already present in the CAS. It is used to
+// construct REv2 Directory messages for the contents of an archive.
+type directoryBuilder struct {
+	directories map[string]*directoryBuilder
+	files       map[string]*remoteexecution.FileNode
+	symlinks    map[string]*remoteexecution.SymlinkNode
+}
+
+func newDirectoryBuilder() *directoryBuilder {
+	return &directoryBuilder{
+		directories: map[string]*directoryBuilder{},
+		files:       map[string]*remoteexecution.FileNode{},
+		symlinks:    map[string]*remoteexecution.SymlinkNode{},
+	}
+}
+
+// splitArchivePath normalizes a path of an entry in an archive and
+// splits it into its components. Paths that attempt to escape the root
+// of the archive are rejected.
+func splitArchivePath(p string) ([]string, error) {
+	cleaned := path.Clean("/" + p)
+	if cleaned == "/" {
+		return nil, nil
+	}
+	for _, component := range strings.Split(p, "/") {
+		if component == ".." {
+			return nil, status.Errorf(codes.InvalidArgument, "Path %#v escapes the root directory", p)
+		}
+	}
+	return strings.Split(cleaned[1:], "/"), nil
+}
+
+// remove any existing node with a given name from the directory.
+func (db *directoryBuilder) remove(name string) {
+	delete(db.directories, name)
+	delete(db.files, name)
+	delete(db.symlinks, name)
+}
+
+// lookupDirectory returns the directory at the given path, creating it
+// and any of its parents if they do not exist yet.
+func (db *directoryBuilder) lookupDirectory(components []string) *directoryBuilder {
+	d := db
+	for _, component := range components {
+		child, ok := d.directories[component]
+		if !ok {
+			d.remove(component)
+			child = newDirectoryBuilder()
+			d.directories[component] = child
+		}
+		d = child
+	}
+	return d
+}
+
+// lookupParent returns the parent directory of a path, together with
+// the name of the entry within that directory.
+func (db *directoryBuilder) lookupParent(p string) (*directoryBuilder, string, error) {
+	components, err := splitArchivePath(p)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(components) == 0 {
+		return nil, "", status.Errorf(codes.InvalidArgument, "Path %#v does not have a name", p)
+	}
+	return db.lookupDirectory(components[:len(components)-1]), components[len(components)-1], nil
+}
+
+// LookupSubdirectory returns the existing directory at a given path.
+func (db *directoryBuilder) LookupSubdirectory(components []string) (*directoryBuilder, error) {
+	d := db
+	for i, component := range components {
+		child, ok := d.directories[component]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "Directory %#v does not exist", strings.Join(components[:i+1], "/"))
+		}
+		d = child
+	}
+	return d, nil
+}
+
+// AddDirectory creates an empty directory at a given path, if it does
+// not exist already.
+func (db *directoryBuilder) AddDirectory(p string) error {
+	components, err := splitArchivePath(p)
+	if err != nil {
+		return err
+	}
+	db.lookupDirectory(components)
+	return nil
+}
+
+// AddFile creates a regular file at a given path, whose contents are
+// stored in the CAS.
+func (db *directoryBuilder) AddFile(p string, digest bb_digest.Digest, isExecutable bool) error {
+	parent, name, err := db.lookupParent(p)
+	if err != nil {
+		return err
+	}
+	parent.remove(name)
+	parent.files[name] = &remoteexecution.FileNode{
+		Name:         name,
+		Digest:       digest.GetProto(),
+		IsExecutable: isExecutable,
+	}
+	return nil
+}
+
+// AddSymlink creates a symbolic link at a given path.
+func (db *directoryBuilder) AddSymlink(p, target string) error {
+	parent, name, err := db.lookupParent(p)
+	if err != nil {
+		return err
+	}
+	parent.remove(name)
+	parent.symlinks[name] = &remoteexecution.SymlinkNode{
+		Name:   name,
+		Target: target,
+	}
+	return nil
+}
+
+// AddHardlink creates a regular file at a given path, having the same
+// contents as a regular file that was added previously.
+func (db *directoryBuilder) AddHardlink(p, target string) error {
+	targetParent, targetName, err := db.lookupParent(target)
+	if err != nil {
+		return err
+	}
+	targetFile, ok := targetParent.files[targetName]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "Hard link %#v refers to %#v, which is not a regular file", p, target)
+	}
+	parent, name, err := db.lookupParent(p)
+	if err != nil {
+		return err
+	}
+	parent.remove(name)
+	parent.files[name] = &remoteexecution.FileNode{
+		Name:         name,
+		Digest:       targetFile.Digest,
+		IsExecutable: targetFile.IsExecutable,
+	}
+	return nil
+}
+
+// Upload writes Directory messages for this directory and all of its
+// descendants into the CAS, returning the digest of this directory.
+func (db *directoryBuilder) Upload(ctx context.Context, contentAddressableStorage blobstore.BlobAccess, digestFunction bb_digest.Function) (bb_digest.Digest, error) {
+	directory := &remoteexecution.Directory{}
+	for name, child := range db.directories {
+		childDigest, err := child.Upload(ctx, contentAddressableStorage, digestFunction)
+		if err != nil {
+			return bb_digest.BadDigest, err
+		}
+		directory.Directories = append(directory.Directories, &remoteexecution.DirectoryNode{
+			Name:   name,
+			Digest: childDigest.GetProto(),
+		})
+	}
+	for _, file := range db.files {
+		directory.Files = append(directory.Files, file)
+	}
+	for _, symlink := range db.symlinks {
+		directory.Symlinks = append(directory.Symlinks, symlink)
+	}
+	sort.Slice(directory.Directories, func(i, j int) bool { return directory.Directories[i].Name < directory.Directories[j].Name })
+	sort.Slice(directory.Files, func(i, j int) bool { return directory.Files[i].Name < directory.Files[j].Name })
+	sort.Slice(directory.Symlinks, func(i, j int) bool { return directory.Symlinks[i].Name < directory.Symlinks[j].Name })
+
+	directoryPb, err := proto.Marshal(directory)
+	if err != nil {
+		return bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal directory")
+	}
+	digestGenerator := digestFunction.NewGenerator(int64(len(directoryPb)))
+	digestGenerator.Write(directoryPb)
+	digest := digestGenerator.Sum()
+	if err := contentAddressableStorage.Put(ctx, digest, buffer.NewCASBufferFromByteSlice(digest, directoryPb, buffer.UserProvided)); err != nil {
+		return bb_digest.BadDigest, util.StatusWrap(err, "Failed to place directory into CAS")
+	}
+	return digest, nil
+}
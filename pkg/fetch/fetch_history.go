@@ -0,0 +1,178 @@
+package fetch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"google.golang.org/grpc/status"
+)
+
+// FetchHistoryEntry describes the outcome of a single call to
+// FetchBlob() or FetchDirectory().
+type FetchHistoryEntry struct {
+	// The time at which the fetch started, and the amount of time it
+	// took to complete.
+	StartTime time.Time
+	Duration  time.Duration
+
+	// The name of the operation, either "FetchBlob" or
+	// "FetchDirectory".
+	Operation    string
+	InstanceName bb_digest.InstanceName
+	URIs         []string
+	Qualifiers   []*remoteasset.Qualifier
+
+	// The outcome of the fetch. URI and Digest are only set if the
+	// fetch succeeded.
+	Status *status.Status
+	URI    string
+	Digest *remoteexecution.Digest
+}
+
+// fetchHistoryRing is a fixed size ring buffer of FetchHistoryEntry.
+type fetchHistoryRing struct {
+	entries []FetchHistoryEntry
+	next    int
+	full    bool
+}
+
+func (r *fetchHistoryRing) add(entry FetchHistoryEntry) {
+	if len(r.entries) == 0 {
+		return
+	}
+	r.entries[r.next] = entry
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+func (r *fetchHistoryRing) get() []FetchHistoryEntry {
+	count := r.next
+	if r.full {
+		count = len(r.entries)
+	}
+	entries := make([]FetchHistoryEntry, 0, count)
+	for i := 1; i <= count; i++ {
+		entries = append(entries, r.entries[(r.next-i+len(r.entries))%len(r.entries)])
+	}
+	return entries
+}
+
+// FetchHistory keeps track of the most recent fetches performed by the
+// server, so that they can be displayed by the web UI. As errors tend
+// to be of most interest, failed fetches are additionally stored in a
+// separate buffer, preventing them from being pushed out by successful
+// fetches.
+type FetchHistory struct {
+	lock    sync.Mutex
+	fetches fetchHistoryRing
+	errors  fetchHistoryRing
+}
+
+// NewFetchHistory creates a FetchHistory that retains up to size
+// fetches and up to size failed fetches.
+func NewFetchHistory(size int) *FetchHistory {
+	return &FetchHistory{
+		fetches: fetchHistoryRing{entries: make([]FetchHistoryEntry, size)},
+		errors:  fetchHistoryRing{entries: make([]FetchHistoryEntry, size)},
+	}
+}
+
+// Add an entry to the history.
+func (h *FetchHistory) Add(entry FetchHistoryEntry) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.fetches.add(entry)
+	if entry.Status.Err() != nil {
+		h.errors.add(entry)
+	}
+}
+
+// GetFetches returns the most recent fetches, newest first.
+func (h *FetchHistory) GetFetches() []FetchHistoryEntry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.fetches.get()
+}
+
+// GetErrors returns the most recent failed fetches, newest first.
+func (h *FetchHistory) GetErrors() []FetchHistoryEntry {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.errors.get()
+}
+
+type historyRecordingFetcher struct {
+	fetcher Fetcher
+	history *FetchHistory
+	clock   clock.Clock
+}
+
+// NewHistoryRecordingFetcher creates a decorator for Fetcher that
+// records the outcome of all fetches in a FetchHistory.
+func NewHistoryRecordingFetcher(fetcher Fetcher, history *FetchHistory, clock clock.Clock) Fetcher {
+	return &historyRecordingFetcher{
+		fetcher: fetcher,
+		history: history,
+		clock:   clock,
+	}
+}
+
+func (hf *historyRecordingFetcher) record(operation, instanceName string, uris []string, qualifiers []*remoteasset.Qualifier, startTime time.Time, responseStatus *status.Status, uri string, digest *remoteexecution.Digest) {
+	// Requests with invalid instance names are rejected before
+	// anything is fetched, so there is no need to record them.
+	parsedInstanceName, err := bb_digest.NewInstanceName(instanceName)
+	if err != nil {
+		return
+	}
+	entry := FetchHistoryEntry{
+		StartTime:    startTime,
+		Duration:     hf.clock.Now().Sub(startTime),
+		Operation:    operation,
+		InstanceName: parsedInstanceName,
+		URIs:         uris,
+		// The history is displayed to other users, meaning
+		// credentials must not be retained.
+		Qualifiers: qualifier.Redact(qualifiers),
+		Status:     responseStatus,
+	}
+	if responseStatus.Err() == nil {
+		entry.URI = uri
+		entry.Digest = digest
+	}
+	hf.history.Add(entry)
+}
+
+func (hf *historyRecordingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+	startTime := hf.clock.Now()
+	resp, err := hf.fetcher.FetchBlob(ctx, req)
+	if err != nil {
+		hf.record("FetchBlob", req.InstanceName, req.Uris, req.Qualifiers, startTime, status.Convert(err), "", nil)
+	} else {
+		hf.record("FetchBlob", req.InstanceName, req.Uris, req.Qualifiers, startTime, status.FromProto(resp.Status), resp.Uri, resp.BlobDigest)
+	}
+	return resp, err
+}
+
+func (hf *historyRecordingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	startTime := hf.clock.Now()
+	resp, err := hf.fetcher.FetchDirectory(ctx, req)
+	if err != nil {
+		hf.record("FetchDirectory", req.InstanceName, req.Uris, req.Qualifiers, startTime, status.Convert(err), "", nil)
+	} else {
+		hf.record("FetchDirectory", req.InstanceName, req.Uris, req.Qualifiers, startTime, status.FromProto(resp.Status), resp.Uri, resp.RootDirectoryDigest)
+	}
+	return resp, err
+}
+
+func (hf *historyRecordingFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
+	return hf.fetcher.CheckQualifiers(qualifiers)
+}
@@ -0,0 +1,133 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFetchHistory(t *testing.T) {
+	history := fetch.NewFetchHistory(2)
+	require.Empty(t, history.GetFetches())
+	require.Empty(t, history.GetErrors())
+
+	// Entries should be returned newest first, with older entries
+	// being discarded once the buffer is full. Errors should be
+	// retained separately.
+	history.Add(fetch.FetchHistoryEntry{URI: "https://example.com/1", Status: status.New(codes.OK, "")})
+	history.Add(fetch.FetchHistoryEntry{URIs: []string{"https://example.com/2"}, Status: status.New(codes.NotFound, "Not found")})
+	history.Add(fetch.FetchHistoryEntry{URI: "https://example.com/3", Status: status.New(codes.OK, "")})
+	history.Add(fetch.FetchHistoryEntry{URI: "https://example.com/4", Status: status.New(codes.OK, "")})
+
+	fetches := history.GetFetches()
+	require.Len(t, fetches, 2)
+	require.Equal(t, "https://example.com/4", fetches[0].URI)
+	require.Equal(t, "https://example.com/3", fetches[1].URI)
+
+	errors := history.GetErrors()
+	require.Len(t, errors, 1)
+	require.Equal(t, []string{"https://example.com/2"}, errors[0].URIs)
+}
+
+func TestHistoryRecordingFetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	history := fetch.NewFetchHistory(10)
+	fetcher := fetch.NewHistoryRecordingFetcher(baseFetcher, history, clock)
+
+	uri := "https://example.com/example.txt"
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+
+	t.Run("Success", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		}
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(&remoteasset.FetchBlobResponse{
+			Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+			Uri:        uri,
+			BlobDigest: blobDigest,
+		}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1002, 0))
+
+		_, err := fetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+
+		fetches := history.GetFetches()
+		require.Len(t, fetches, 1)
+		require.Equal(t, time.Unix(1000, 0), fetches[0].StartTime)
+		require.Equal(t, 2*time.Second, fetches[0].Duration)
+		require.Equal(t, "FetchBlob", fetches[0].Operation)
+		require.Equal(t, bb_digest.MustNewInstanceName("instance"), fetches[0].InstanceName)
+		require.Equal(t, uri, fetches[0].URI)
+		require.Equal(t, blobDigest, fetches[0].Digest)
+		require.Empty(t, history.GetErrors())
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		request := &remoteasset.FetchDirectoryRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+		}
+		clock.EXPECT().Now().Return(time.Unix(1010, 0))
+		baseFetcher.EXPECT().FetchDirectory(ctx, request).Return(nil, status.Error(codes.Unavailable, "Server unreachable"))
+		clock.EXPECT().Now().Return(time.Unix(1011, 0))
+
+		_, err := fetcher.FetchDirectory(ctx, request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+
+		errors := history.GetErrors()
+		require.Len(t, errors, 1)
+		require.Equal(t, "FetchDirectory", errors[0].Operation)
+		require.Equal(t, codes.Unavailable, errors[0].Status.Code())
+		require.Equal(t, "Server unreachable", errors[0].Status.Message())
+		require.Len(t, history.GetFetches(), 2)
+	})
+
+	t.Run("Credentials", func(t *testing.T) {
+		// The history is displayed to other users, so it must
+		// not contain any credentials.
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "auth.basic.password", Value: "hunter2"},
+				{Name: "bazel.auth_headers", Value: `{"https://example.com/example.txt": {"Authorization": "Bearer secret"}}`},
+				{Name: "checksum.sri", Value: "sha256-0NgpxMDOZHh8scmYqcKaEJ+O0AVjMTL9ov4SmCSHBNs="},
+			},
+		}
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(&remoteasset.FetchBlobResponse{
+			Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+			Uri:        uri,
+			BlobDigest: blobDigest,
+		}, nil)
+		clock.EXPECT().Now().Return(time.Unix(1021, 0))
+
+		_, err := fetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+
+		fetches := history.GetFetches()
+		require.Len(t, fetches, 3)
+		testutil.RequireEqualProto(t, &remoteasset.Qualifier{Name: "auth.basic.password", Value: "[redacted]"}, fetches[0].Qualifiers[0])
+		testutil.RequireEqualProto(t, &remoteasset.Qualifier{Name: "bazel.auth_headers", Value: "[redacted]"}, fetches[0].Qualifiers[1])
+		testutil.RequireEqualProto(t, request.Qualifiers[2], fetches[0].Qualifiers[2])
+
+		// The request itself must not be modified.
+		require.Equal(t, "hunter2", request.Qualifiers[0].Value)
+	})
+}
@@ -0,0 +1,206 @@
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+)
+
+// HTTPCredentialProvider provides headers that the HTTP fetcher
+// attaches to requests in order to authenticate against the server.
+type HTTPCredentialProvider interface {
+	// GetHeaders returns the headers to attach to a request for a
+	// given URI, or nil if the provider has no credentials for it.
+	GetHeaders(ctx context.Context, uri string) (http.Header, error)
+}
+
+// HTTPCredentialPrecedence determines how headers obtained from an
+// HTTPCredentialProvider are combined with the headers provided by
+// the client through the 'bazel.auth_headers' qualifier.
+type HTTPCredentialPrecedence int
+
+const (
+	// HTTPCredentialPrecedenceClient causes headers provided by the
+	// client to override headers of the same name provided by the
+	// server.
+	HTTPCredentialPrecedenceClient HTTPCredentialPrecedence = iota
+	// HTTPCredentialPrecedenceServer causes headers provided by the
+	// server to override headers of the same name provided by the
+	// client.
+	HTTPCredentialPrecedenceServer
+	// HTTPCredentialPrecedenceServerExclusive causes headers provided
+	// by the client to be ignored entirely for URIs for which the
+	// server has credentials.
+	HTTPCredentialPrecedenceServerExclusive
+)
+
+type noHTTPCredentialProvider struct{}
+
+func (noHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	return nil, nil
+}
+
+// NoHTTPCredentials is an HTTPCredentialProvider that never provides
+// any credentials.
+var NoHTTPCredentials HTTPCredentialProvider = noHTTPCredentialProvider{}
+
+type staticHTTPCredentialProvider struct {
+	header http.Header
+}
+
+// NewStaticHTTPCredentialProvider creates an HTTPCredentialProvider
+// that provides a fixed set of headers for all URIs.
+func NewStaticHTTPCredentialProvider(header http.Header) HTTPCredentialProvider {
+	return &staticHTTPCredentialProvider{
+		header: header,
+	}
+}
+
+func (cp *staticHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	return cp.header, nil
+}
+
+// NewBasicAuthenticationHTTPCredentialProvider creates an
+// HTTPCredentialProvider that provides a username and password for
+// HTTP basic authentication for all URIs.
+func NewBasicAuthenticationHTTPCredentialProvider(username, password string) HTTPCredentialProvider {
+	return NewStaticHTTPCredentialProvider(newBasicAuthHeader(username, password))
+}
+
+type bearerTokenFileHTTPCredentialProvider struct {
+	file *reloadingFile
+}
+
+// NewBearerTokenFileHTTPCredentialProvider creates an
+// HTTPCredentialProvider that provides an 'Authorization' header
+// containing a bearer token that is read from a file. The file is
+// read again whenever it is modified, permitting the token to be
+// rotated without restarting.
+func NewBearerTokenFileHTTPCredentialProvider(path string) HTTPCredentialProvider {
+	return &bearerTokenFileHTTPCredentialProvider{
+		file: newReloadingFile(path),
+	}
+}
+
+func (cp *bearerTokenFileHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	contents, err := cp.file.Read()
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	header.Set("Authorization", "Bearer "+string(bytes.TrimSpace(contents)))
+	return header, nil
+}
+
+type hostMatchingHTTPCredentialProvider struct {
+	base HTTPCredentialProvider
+	host string
+}
+
+// NewHostMatchingHTTPCredentialProvider creates a decorator for
+// HTTPCredentialProvider that only provides credentials for URIs
+// whose host is equal to a given host.
+func NewHostMatchingHTTPCredentialProvider(base HTTPCredentialProvider, host string) HTTPCredentialProvider {
+	return &hostMatchingHTTPCredentialProvider{
+		base: base,
+		host: host,
+	}
+}
+
+func (cp *hostMatchingHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	if parsed, err := url.Parse(uri); err != nil || parsed.Hostname() != cp.host {
+		return nil, nil
+	}
+	return cp.base.GetHeaders(ctx, uri)
+}
+
+type urlPrefixMatchingHTTPCredentialProvider struct {
+	base      HTTPCredentialProvider
+	urlPrefix string
+}
+
+// NewURLPrefixMatchingHTTPCredentialProvider creates a decorator for
+// HTTPCredentialProvider that only provides credentials for URIs
+// starting with a given prefix.
+func NewURLPrefixMatchingHTTPCredentialProvider(base HTTPCredentialProvider, urlPrefix string) HTTPCredentialProvider {
+	return &urlPrefixMatchingHTTPCredentialProvider{
+		base:      base,
+		urlPrefix: urlPrefix,
+	}
+}
+
+func (cp *urlPrefixMatchingHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	if !strings.HasPrefix(uri, cp.urlPrefix) {
+		return nil, nil
+	}
+	return cp.base.GetHeaders(ctx, uri)
+}
+
+type firstMatchHTTPCredentialProvider struct {
+	providers []HTTPCredentialProvider
+}
+
+// NewFirstMatchHTTPCredentialProvider creates an
+// HTTPCredentialProvider that returns the credentials of the first
+// provider in a list that has credentials for a URI.
+func NewFirstMatchHTTPCredentialProvider(providers []HTTPCredentialProvider) HTTPCredentialProvider {
+	return &firstMatchHTTPCredentialProvider{
+		providers: providers,
+	}
+}
+
+func (cp *firstMatchHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	for _, provider := range cp.providers {
+		if header, err := provider.GetHeaders(ctx, uri); err != nil || header != nil {
+			return header, err
+		}
+	}
+	return nil, nil
+}
+
+// reloadingFile holds the contents of a file, reading it again if its
+// modification time or size have changed since it was last read.
+type reloadingFile struct {
+	path string
+
+	lock     sync.Mutex
+	modTime  time.Time
+	size     int64
+	contents []byte
+}
+
+func newReloadingFile(path string) *reloadingFile {
+	return &reloadingFile{
+		path: path,
+	}
+}
+
+// Read returns the current contents of the file.
+func (rf *reloadingFile) Read() ([]byte, error) {
+	info, err := os.Stat(rf.path)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Unavailable, "Failed to stat %#v", rf.path)
+	}
+
+	rf.lock.Lock()
+	defer rf.lock.Unlock()
+	if rf.contents != nil && info.ModTime().Equal(rf.modTime) && info.Size() == rf.size {
+		return rf.contents, nil
+	}
+	contents, err := os.ReadFile(rf.path)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Unavailable, "Failed to read %#v", rf.path)
+	}
+	rf.modTime = info.ModTime()
+	rf.size = info.Size()
+	rf.contents = contents
+	return contents, nil
+}
@@ -0,0 +1,101 @@
+package fetch_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBearerTokenFileHTTPCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	provider := fetch.NewBearerTokenFileHTTPCredentialProvider(tokenPath)
+
+	t.Run("Missing", func(t *testing.T) {
+		_, err := provider.GetHeaders(ctx, "https://example.com/file.tar.gz")
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("Reload", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(tokenPath, []byte("token1\n"), 0o600))
+		header, err := provider.GetHeaders(ctx, "https://example.com/file.tar.gz")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token1", header.Get("Authorization"))
+
+		// Rotating the token should be picked up without
+		// recreating the provider.
+		require.NoError(t, os.WriteFile(tokenPath, []byte("token22\n"), 0o600))
+		require.NoError(t, os.Chtimes(tokenPath, time.Now(), time.Now().Add(time.Minute)))
+		header, err = provider.GetHeaders(ctx, "https://example.com/file.tar.gz")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token22", header.Get("Authorization"))
+	})
+}
+
+func TestNetrcHTTPCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	netrcPath := filepath.Join(t.TempDir(), ".netrc")
+	require.NoError(t, os.WriteFile(netrcPath, []byte(`
+machine example.com
+  login alice
+  password secret
+macdef init
+  cd /pub
+
+machine example.org login bob password hunter2
+default login anonymous password guest
+`), 0o600))
+	provider := fetch.NewNetrcHTTPCredentialProvider(netrcPath)
+
+	for uri, expected := range map[string][2]string{
+		"https://example.com/file.tar.gz":   {"alice", "secret"},
+		"https://example.org:8080/file.zip": {"bob", "hunter2"},
+		"https://example.net/file.zip":      {"anonymous", "guest"},
+	} {
+		header, err := provider.GetHeaders(ctx, uri)
+		require.NoError(t, err)
+		req := http.Request{Header: header}
+		username, password, ok := req.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, expected, [2]string{username, password})
+	}
+
+	t.Run("Malformed", func(t *testing.T) {
+		malformedPath := filepath.Join(t.TempDir(), ".netrc")
+		require.NoError(t, os.WriteFile(malformedPath, []byte("machine example.com login"), 0o600))
+		_, err := fetch.NewNetrcHTTPCredentialProvider(malformedPath).GetHeaders(ctx, "https://example.com/")
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestFirstMatchHTTPCredentialProvider(t *testing.T) {
+	ctx := context.Background()
+	provider := fetch.NewFirstMatchHTTPCredentialProvider([]fetch.HTTPCredentialProvider{
+		fetch.NewURLPrefixMatchingHTTPCredentialProvider(
+			fetch.NewStaticHTTPCredentialProvider(http.Header{"X-Token": []string{"private"}}),
+			"https://example.com/private/"),
+		fetch.NewHostMatchingHTTPCredentialProvider(
+			fetch.NewBasicAuthenticationHTTPCredentialProvider("alice", "secret"),
+			"example.com"),
+	})
+
+	header, err := provider.GetHeaders(ctx, "https://example.com/private/file.zip")
+	require.NoError(t, err)
+	require.Equal(t, http.Header{"X-Token": []string{"private"}}, header)
+
+	header, err = provider.GetHeaders(ctx, "https://example.com/public/file.zip")
+	require.NoError(t, err)
+	require.Equal(t, "Basic YWxpY2U6c2VjcmV0", header.Get("Authorization"))
+
+	header, err = provider.GetHeaders(ctx, "https://example.org/file.zip")
+	require.NoError(t, err)
+	require.Nil(t, header)
+}
@@ -1,20 +1,23 @@
 package fetch
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/base64"
 	"encoding/hex"
 	"io"
 	"log"
+	"math"
 	"net/http"
-	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
 
@@ -24,19 +27,72 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+var (
+	httpFetcherPrometheusMetrics sync.Once
+
+	httpFetcherRequestAttempts = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "remote_asset",
+			Name:      "http_fetcher_request_attempts_total",
+			Help:      "Number of attempts made to perform HTTP requests, by outcome.",
+		},
+		[]string{"outcome"})
+	httpFetcherRequestAttemptsSucceeded = httpFetcherRequestAttempts.WithLabelValues("Succeeded")
+	httpFetcherRequestAttemptsRetried   = httpFetcherRequestAttempts.WithLabelValues("Retried")
+	httpFetcherRequestAttemptsFailed    = httpFetcherRequestAttempts.WithLabelValues("Failed")
+)
+
 type httpFetcher struct {
-	httpClient                *http.Client
-	contentAddressableStorage blobstore.BlobAccess
+	httpClient                   *http.Client
+	contentAddressableStorage    blobstore.BlobAccess
+	maximumInMemoryBodySizeBytes int64
+	spoolDirectoryPath           string
+	retryPolicy                  HTTPRetryPolicy
+	maximumResumeAttempts        int
+	credentialProvider           HTTPCredentialProvider
+	credentialPrecedence         HTTPCredentialPrecedence
+	clock                        clock.Clock
 }
 
 // NewHTTPFetcher creates a remoteasset FetchServer compatible service for handling requests which involve downloading
 // assets over HTTP and storing them into a CAS.
+//
+// Response bodies whose digest is not known up front are spooled while
+// their digest is computed. Up to maximumInMemoryBodySizeBytes of such
+// a body is held in memory, beyond which it is spooled to a temporary
+// file in spoolDirectoryPath.
+//
+// Requests that fail transiently are retried according to retryPolicy.
+// Downloads of response bodies that are interrupted are resumed up to
+// maximumResumeAttempts times, regardless of retryPolicy.
+// Requests are authenticated using the headers provided by
+// credentialProvider, which are combined with the headers provided by
+// the client according to credentialPrecedence.
 func NewHTTPFetcher(httpClient *http.Client,
 	contentAddressableStorage blobstore.BlobAccess,
+	maximumInMemoryBodySizeBytes int64,
+	spoolDirectoryPath string,
+	retryPolicy HTTPRetryPolicy,
+	maximumResumeAttempts int,
+	credentialProvider HTTPCredentialProvider,
+	credentialPrecedence HTTPCredentialPrecedence,
+	clock clock.Clock,
 ) Fetcher {
+	httpFetcherPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(httpFetcherRequestAttempts)
+	})
+
 	return &httpFetcher{
-		httpClient:                httpClient,
-		contentAddressableStorage: contentAddressableStorage,
+		httpClient:                   httpClient,
+		contentAddressableStorage:    contentAddressableStorage,
+		maximumInMemoryBodySizeBytes: maximumInMemoryBodySizeBytes,
+		spoolDirectoryPath:           spoolDirectoryPath,
+		retryPolicy:                  retryPolicy,
+		maximumResumeAttempts:        maximumResumeAttempts,
+		credentialProvider:           credentialProvider,
+		credentialPrecedence:         credentialPrecedence,
+		clock:                        clock,
 	}
 }
 
@@ -47,10 +103,9 @@ func (hf *httpFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlob
 		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
 	}
 
-	// TODO: Address the following fields
-	// timeout := ptypes.Duration(req.timeout)
-	// oldestContentAccepted := ptypes.Timestamp(req.oldestContentAccepted)
-	expectedDigest, err := getChecksumSri(req.Qualifiers)
+	// req.Timeout is enforced by TimeoutFetcher, while
+	// req.OldestContentAccepted is only relevant to CachingFetcher.
+	checksums, err := getChecksumSri(req.Qualifiers)
 	if err != nil {
 		return nil, err
 	}
@@ -60,16 +115,40 @@ func (hf *httpFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlob
 		return nil, err
 	}
 
+	// The digest in the CAS is always computed using the digest
+	// function of the instance, regardless of the algorithms used by
+	// the checksums provided by the client.
+	digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, sha256.Size*2)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to get digest function for instance: %v", instanceName)
+	}
+
+	var failureCodes []codes.Code
 	for _, uri := range req.Uris {
 
-		buffer, digest := hf.downloadBlob(ctx, uri, instanceName, expectedDigest, auth)
+		// Response bodies that are streamed into the CAS are
+		// only validated against the checksum while being
+		// written. Track whether validation failed, so that
+		// mismatches can be told apart from CAS failures.
+		var checksumMismatch atomic.Bool
+		buffer, digest := hf.downloadBlob(ctx, uri, digestFunction, checksums, auth, func(dataIsValid bool) {
+			if !dataIsValid {
+				checksumMismatch.Store(true)
+			}
+		})
 		if _, err = buffer.GetSizeBytes(); err != nil {
 			log.Printf("Error downloading blob with URI %s: %v", uri, err)
+			failureCodes = append(failureCodes, status.Code(err))
 			continue
 		}
 
 		if err = hf.contentAddressableStorage.Put(ctx, digest, buffer); err != nil {
 			log.Printf("Error downloading blob with URI %s: %v", uri, err)
+			if checksumMismatch.Load() {
+				err = util.StatusWrapWithCode(err, codes.NotFound, "Response body does not match the expected checksum")
+				failureCodes = append(failureCodes, codes.NotFound)
+				continue
+			}
 			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to place blob into CAS")
 		}
 		return &remoteasset.FetchBlobResponse{
@@ -80,101 +159,311 @@ func (hf *httpFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlob
 		}, nil
 	}
 
-	return nil, util.StatusWrapWithCode(err, codes.NotFound, "Unable to download blob from any provided URI")
+	return nil, util.StatusWrapWithCode(err, getFetchFailureCode(failureCodes), "Unable to download blob from any provided URI")
 }
 
 func (hf *httpFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
-	return nil, status.Errorf(codes.PermissionDenied, "HTTP Fetching of directories is not supported!")
+	instanceName, err := bb_digest.NewInstanceName(req.InstanceName)
+	if err != nil {
+		return nil, util.StatusWrapf(err, "Invalid instance name %#v", req.InstanceName)
+	}
+
+	checksums, err := getChecksumSri(req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := getAuthHeaders(req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := getArchiveFormat(req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	stripPrefix, err := getStripPrefix(req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+
+	digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, sha256.Size*2)
+	if err != nil {
+		return nil, util.StatusWrapfWithCode(err, codes.Internal, "Failed to get digest function for instance: %v", instanceName)
+	}
+
+	var failureCodes []codes.Code
+	for _, uri := range req.Uris {
+		var rootDigest bb_digest.Digest
+		rootDigest, err = hf.downloadDirectory(ctx, uri, digestFunction, checksums, auth, format, stripPrefix)
+		if err != nil {
+			log.Printf("Error downloading directory with URI %s: %v", uri, err)
+			failureCodes = append(failureCodes, status.Code(err))
+			continue
+		}
+		return &remoteasset.FetchDirectoryResponse{
+			Status:              status.New(codes.OK, "Directory fetched successfully!").Proto(),
+			Uri:                 uri,
+			Qualifiers:          req.Qualifiers,
+			RootDirectoryDigest: rootDigest.GetProto(),
+		}, nil
+	}
+
+	return nil, util.StatusWrapWithCode(err, getFetchFailureCode(failureCodes), "Unable to download directory from any provided URI")
 }
 
 func (hf *httpFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
-	return qualifier.Difference(qualifiers, qualifier.NewSet([]string{"checksum.sri", "bazel.auth_headers", "bazel.canonical_id"}))
+	return qualifier.Difference(qualifiers, qualifier.NewSet([]string{"checksum.sri", "bazel.auth_headers", "bazel.canonical_id", "bazel.archive_type", "bazel.strip_prefix"}))
+}
+
+// doRequest performs a HTTP GET request for a given URI, returning
+// the response if the server responded with the expected status code.
+// Requests that fail transiently are retried, as long as the deadline
+// of the context permits it.
+func (hf *httpFetcher) doRequest(ctx context.Context, uri string, auth *AuthHeaders, header http.Header, expectedStatusCode int) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		resp, retryable, retryAfter, err := hf.doSingleRequest(ctx, uri, auth, header, expectedStatusCode)
+		if err == nil {
+			httpFetcherRequestAttemptsSucceeded.Inc()
+			return resp, nil
+		}
+		if !retryable || attempt >= hf.retryPolicy.MaximumAttempts {
+			httpFetcherRequestAttemptsFailed.Inc()
+			log.Printf("Attempt %d to download URI %s failed: %v", attempt, uri, err)
+			return nil, err
+		}
+
+		delay := hf.retryPolicy.getBackoff(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if deadline, ok := ctx.Deadline(); ok && hf.clock.Now().Add(delay).After(deadline) {
+			httpFetcherRequestAttemptsFailed.Inc()
+			log.Printf("Attempt %d to download URI %s failed, not retrying as the deadline would be exceeded: %v", attempt, uri, err)
+			return nil, err
+		}
+		httpFetcherRequestAttemptsRetried.Inc()
+		log.Printf("Attempt %d to download URI %s failed, retrying in %s: %v", attempt, uri, delay, err)
+
+		timer, t := hf.clock.NewTimer(delay)
+		select {
+		case <-t:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, util.StatusFromContext(ctx)
+		}
+	}
 }
 
-func (hf *httpFetcher) downloadBlob(ctx context.Context, uri string, instanceName bb_digest.InstanceName, expectedDigest string, auth *AuthHeaders) (buffer.Buffer, bb_digest.Digest) {
+// doSingleRequest performs a single attempt at a HTTP GET request. If
+// the attempt fails, it reports whether the failure is transient and
+// how long the server requested to wait before retrying.
+func (hf *httpFetcher) doSingleRequest(ctx context.Context, uri string, auth *AuthHeaders, header http.Header, expectedStatusCode int) (*http.Response, bool, time.Duration, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
-		return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Failed to create HTTP request")), bb_digest.BadDigest
+		return nil, false, 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to create HTTP request")
+	}
+	for name, values := range header {
+		req.Header[name] = values
 	}
 
-	if auth != nil {
-		auth.ApplyHeaders(uri, req)
+	if err := hf.applyCredentials(ctx, uri, req, auth); err != nil {
+		return nil, false, 0, err
 	}
 
 	resp, err := hf.httpClient.Do(req)
 	if err != nil {
-		log.Printf("Error downloading blob with URI %s: %v", uri, err)
-		return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "HTTP request failed")), bb_digest.BadDigest
+		if ctx.Err() != nil {
+			return nil, false, 0, util.StatusFromContext(ctx)
+		}
+		return nil, hf.retryPolicy.RetryTransportErrors, 0, util.StatusWrapWithCode(err, codes.Unavailable, "HTTP request failed")
+	}
+	if resp.StatusCode != expectedStatusCode {
+		if resp.Body != nil {
+			resp.Body.Close()
+		}
+		var retryAfter time.Duration
+		retryable := hf.retryPolicy.isRetryableStatusCode(resp.StatusCode)
+		if retryable {
+			if value := resp.Header.Get("Retry-After"); value != "" {
+				retryAfter, _ = parseRetryAfter(value, hf.clock.Now())
+			}
+		}
+		return nil, retryable, retryAfter, status.Errorf(getHTTPStatusCode(resp.StatusCode), "HTTP request failed with status %#v", resp.Status)
+	}
+	return resp, false, 0, nil
+}
+
+// applyCredentials attaches the headers provided by the credential
+// provider and by the client to a request. The order in which they
+// are applied is determined by the configured precedence.
+func (hf *httpFetcher) applyCredentials(ctx context.Context, uri string, req *http.Request, auth *AuthHeaders) error {
+	serverHeader, err := hf.credentialProvider.GetHeaders(ctx, uri)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Unauthenticated, "Failed to obtain credentials")
+	}
+	serverAuth := NewAuthHeadersFromHeader(uri, serverHeader)
+	applyServerHeader := func() {
+		serverAuth.ApplyHeaders(uri, req)
+	}
+
+	switch {
+	case auth == nil:
+		applyServerHeader()
+	case serverHeader != nil && hf.credentialPrecedence == HTTPCredentialPrecedenceServerExclusive:
+		applyServerHeader()
+	case hf.credentialPrecedence == HTTPCredentialPrecedenceClient:
+		applyServerHeader()
+		auth.ApplyHeaders(uri, req)
+	default:
+		auth.ApplyHeaders(uri, req)
+		applyServerHeader()
+	}
+	return nil
+}
+
+// spoolBody reads a response body to completion, computing its digest
+// and verifying it against the checksums provided by the client. The
+// body is spooled, so that large bodies do not need to be held in
+// memory.
+func (hf *httpFetcher) spoolBody(resp *http.Response, digestFunction bb_digest.Function, checksums sriChecksums) (*bodySpool, bb_digest.Digest, error) {
+	expectedSizeBytes := resp.ContentLength
+	if expectedSizeBytes < 0 {
+		expectedSizeBytes = math.MaxInt64
+	}
+	digestGenerator := digestFunction.NewGenerator(expectedSizeBytes)
+	verifier := checksums.newVerifier()
+	spool := newBodySpool(hf.maximumInMemoryBodySizeBytes, hf.spoolDirectoryPath)
+	if _, err := io.Copy(io.MultiWriter(digestGenerator, verifier, spool), resp.Body); err != nil {
+		resp.Body.Close()
+		spool.Discard()
+		return nil, bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.Unavailable, "Failed to read response body")
+	}
+	if err := resp.Body.Close(); err != nil {
+		spool.Discard()
+		return nil, bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.Internal, "Failed to close response body")
 	}
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Error downloading blob with URI %s: %v", uri, resp.StatusCode)
-		return buffer.NewBufferFromError(status.Errorf(codes.Internal, "HTTP request failed with status %#v", resp.Status)), bb_digest.BadDigest
+	if err := verifier.Verify(); err != nil {
+		spool.Discard()
+		return nil, bb_digest.BadDigest, err
 	}
+	return spool, digestGenerator.Sum(), nil
+}
+
+func (hf *httpFetcher) downloadBlob(ctx context.Context, uri string, digestFunction bb_digest.Function, checksums sriChecksums, auth *AuthHeaders, dataIntegrityCallback buffer.DataIntegrityCallback) (buffer.Buffer, bb_digest.Digest) {
+	resp, err := hf.doRequest(ctx, uri, auth, nil, http.StatusOK)
+	if err != nil {
+		return buffer.NewBufferFromError(err), bb_digest.BadDigest
+	}
+	hf.makeResumable(ctx, uri, auth, resp, len(checksums) > 0)
 
-	// Work out the digest of the downloaded data
-	//
 	// If the HTTP response includes the content length (indicated by the value
 	// of the field being >= 0) and the client has provided an expected hash of
-	// the content, we can avoid holding the contents of the entire file in
-	// memory at one time by creating a new buffer from the response body
-	// directly
-	//
-	// If either one (or both) of these things is not available, we will need to
-	// read the enitre response body into a byte slice in order to be able to
-	// determine the digest
-	length := resp.ContentLength
-	body := resp.Body
-	if length < 0 || expectedDigest == "" {
-		bodyBytes, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Failed to read response body")), bb_digest.BadDigest
-		}
-		err = resp.Body.Close()
+	// the content that uses the same digest function as the CAS, we can avoid
+	// holding the contents of the entire file at one time by creating a new
+	// buffer from the response body directly. An error will be generated down
+	// the line if the data does not match the digest.
+	if expectedHash, ok := checksums.getSingleSha256(); ok && resp.ContentLength >= 0 && digestFunction.GetEnumValue() == remoteexecution.DigestFunction_SHA256 {
+		digest, err := digestFunction.NewDigest(hex.EncodeToString(expectedHash), resp.ContentLength)
 		if err != nil {
-			return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Failed to close response body")), bb_digest.BadDigest
-		}
-		length = int64(len(bodyBytes))
-
-		// If we don't know what the hash should be we will need to work out the
-		// actual hash of the content
-		if expectedDigest == "" {
-			hasher := sha256.New()
-			hasher.Write(bodyBytes)
-			hash := hasher.Sum(nil)
-			expectedDigest = hex.EncodeToString(hash)
+			resp.Body.Close()
+			return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Digest Creation failed")), bb_digest.BadDigest
 		}
+		return buffer.NewCASBufferFromReader(digest, resp.Body, buffer.BackendProvided(dataIntegrityCallback)), digest
+	}
+
+	// Otherwise we need to read the entire response body to determine
+	// the digest and to verify it against the checksums.
+	spool, digest, err := hf.spoolBody(resp, digestFunction, checksums)
+	if err != nil {
+		return buffer.NewBufferFromError(err), bb_digest.BadDigest
+	}
+	return spool.ToBuffer(), digest
+}
 
-		body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+// downloadDirectory downloads an archive and extracts it, storing all
+// files and directories contained in it in the CAS. The digest of the
+// directory at the path given by stripPrefix is returned.
+func (hf *httpFetcher) downloadDirectory(ctx context.Context, uri string, digestFunction bb_digest.Function, checksums sriChecksums, auth *AuthHeaders, format archiveFormat, stripPrefix []string) (bb_digest.Digest, error) {
+	resp, err := hf.doRequest(ctx, uri, auth, nil, http.StatusOK)
+	if err != nil {
+		return bb_digest.BadDigest, err
 	}
-	digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(expectedDigest))
+	hf.makeResumable(ctx, uri, auth, resp, len(checksums) > 0)
+	spool, _, err := hf.spoolBody(resp, digestFunction, checksums)
 	if err != nil {
-		return buffer.NewBufferFromError(util.StatusWrapfWithCode(err, codes.Internal, "Failed to get digest function for instance: %v", instanceName)), bb_digest.BadDigest
+		return bb_digest.BadDigest, err
+	}
+	archiveSizeBytes := spool.SizeBytes()
+	archive := spool.ToReaderAt()
+	defer archive.Close()
+
+	root := newDirectoryBuilder()
+	if err := extractArchive(archive, archiveSizeBytes, format, root, func(r io.Reader) (bb_digest.Digest, error) {
+		return hf.putFile(ctx, r, digestFunction)
+	}); err != nil {
+		return bb_digest.BadDigest, err
 	}
-	digest, err := digestFunction.NewDigest(expectedDigest, length)
+	subdirectory, err := root.LookupSubdirectory(stripPrefix)
 	if err != nil {
-		return buffer.NewBufferFromError(util.StatusWrapWithCode(err, codes.Internal, "Digest Creation failed")), bb_digest.BadDigest
+		return bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to strip prefix")
+	}
+	return subdirectory.Upload(ctx, hf.contentAddressableStorage, digestFunction)
+}
+
+// putFile stores the contents of a file extracted from an archive in
+// the CAS.
+func (hf *httpFetcher) putFile(ctx context.Context, r io.Reader, digestFunction bb_digest.Function) (bb_digest.Digest, error) {
+	digestGenerator := digestFunction.NewGenerator(math.MaxInt64)
+	spool := newBodySpool(hf.maximumInMemoryBodySizeBytes, hf.spoolDirectoryPath)
+	if _, err := io.Copy(io.MultiWriter(digestGenerator, spool), r); err != nil {
+		spool.Discard()
+		return bb_digest.BadDigest, util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to read file from archive")
 	}
+	digest := digestGenerator.Sum()
+	if err := hf.contentAddressableStorage.Put(ctx, digest, spool.ToBuffer()); err != nil {
+		return bb_digest.BadDigest, util.StatusWrap(err, "Failed to place file into CAS")
+	}
+	return digest, nil
+}
 
-	// An error will be generated down the line if the data does not match the
-	// digest
-	return buffer.NewCASBufferFromReader(digest, body, buffer.UserProvided), digest
+// getHTTPStatusCode converts the status code of a HTTP response to a
+// gRPC status code. Only responses indicating that the resource does
+// not exist are reported as NOT_FOUND, as NegativeCachingFetcher
+// caches such failures. Server errors and rate limiting are reported
+// as UNAVAILABLE, as they are likely transient.
+func getHTTPStatusCode(statusCode int) codes.Code {
+	switch {
+	case statusCode == http.StatusNotFound || statusCode == http.StatusGone:
+		return codes.NotFound
+	case statusCode == http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case statusCode == http.StatusForbidden:
+		return codes.PermissionDenied
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
 }
 
-func getChecksumSri(qualifiers []*remoteasset.Qualifier) (string, error) {
-	for _, qualifier := range qualifiers {
-		if qualifier.Name == "checksum.sri" {
-			if strings.HasPrefix(qualifier.Value, "sha256-") {
-				b64hash := strings.TrimPrefix(qualifier.Value, "sha256-")
-				decoded, err := base64.StdEncoding.DecodeString(b64hash)
-				if err != nil {
-					return "", status.Errorf(codes.InvalidArgument, "Failed to decode checksum as b64 encoded sha256 sum: %s", err.Error())
-				}
-				return hex.EncodeToString(decoded), nil
-			}
-			return "", status.Errorf(codes.InvalidArgument, "Non sha256 checksums are not supported")
+// getFetchFailureCode returns the status code to return when none of
+// the URIs of a request could be downloaded. The code is only
+// preserved if all URIs failed with the same code, so that clients and
+// NegativeCachingFetcher can distinguish assets that do not exist from
+// transient failures. For example, an asset that is missing from one
+// mirror while another mirror is unavailable may still exist.
+func getFetchFailureCode(failureCodes []codes.Code) codes.Code {
+	if len(failureCodes) == 0 {
+		return codes.NotFound
+	}
+	for _, code := range failureCodes[1:] {
+		if code != failureCodes[0] {
+			return codes.Unavailable
 		}
 	}
-	return "", nil
+	return failureCodes[0]
 }
 
 func getAuthHeaders(qualifiers []*remoteasset.Qualifier) (*AuthHeaders, error) {
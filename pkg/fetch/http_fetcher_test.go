@@ -1,14 +1,26 @@
 package fetch_test
 
 import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"errors"
 	"io"
 	"net/http"
+	"os"
+	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/buildbarn/bb-remote-asset/internal/mock"
 	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
 
 	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
@@ -63,7 +75,7 @@ func TestHTTPFetcherFetchBlob(t *testing.T) {
 	}
 	casBlobAccess := mock.NewMockBlobAccess(ctrl)
 	roundTripper := mock.NewMockRoundTripper(ctrl)
-	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess)
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
 	body := mock.NewMockReadCloser(ctrl)
 	helloDigest := bb_digest.MustNewDigest(
 		"",
@@ -187,6 +199,77 @@ func TestHTTPFetcherFetchBlob(t *testing.T) {
 		require.Equal(t, status.Code(err), codes.NotFound)
 	})
 
+	t.Run("Gone", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:     "410 Gone",
+			StatusCode: 410,
+		}, nil).Times(2)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("ServerError", func(t *testing.T) {
+		// Server errors are likely transient, and should thus
+		// not be reported as NOT_FOUND, as such failures are
+		// cached by NegativeCachingFetcher.
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:     "500 Internal Server Error",
+			StatusCode: 500,
+		}, nil).Times(2)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("PartialServerError", func(t *testing.T) {
+		// The asset may still exist if it's only missing from
+		// some of the URIs, while the others failed transiently.
+		gomock.InOrder(
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+				Status:     "503 Service Unavailable",
+				StatusCode: 503,
+			}, nil),
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+				Status:     "404 Not Found",
+				StatusCode: 404,
+			}, nil))
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("StreamedChecksumMismatch", func(t *testing.T) {
+		// Response bodies with a content length are streamed
+		// into the CAS, meaning that checksum mismatches are only
+		// detected while writing them. These should be reported
+		// as NOT_FOUND as well, and not as CAS failures.
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{
+					Name:  "checksum.sri",
+					Value: "sha256-GF+NsyJx/iX1Yab8k4suJkMG7DBO2lGAB9F2SCY4GWk=",
+				},
+			},
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(strings.NewReader("Hallo")),
+			ContentLength: 5,
+		}, nil)
+		casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+				_, err := b.ToByteSlice(10)
+				return err
+			})
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
 	t.Run("WithAuthHeaders", func(t *testing.T) {
 		request := &remoteasset.FetchBlobRequest{
 			InstanceName: "",
@@ -222,18 +305,671 @@ func TestHTTPFetcherFetchBlob(t *testing.T) {
 	})
 }
 
+func TestHTTPFetcherFetchBlobSpooling(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "www.example.com"
+	casBlobAccess := mock.NewMockBlobAccess(ctrl)
+	roundTripper := mock.NewMockRoundTripper(ctrl)
+	spoolDirectory := t.TempDir()
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 2, spoolDirectory, fetch.NoHTTPRetries, 0, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
+	helloDigest := bb_digest.MustNewDigest(
+		"",
+		remoteexecution.DigestFunction_SHA256,
+		"185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		5,
+	)
+
+	t.Run("SpooledToDisk", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(strings.NewReader("Hello")),
+			ContentLength: -1,
+		}, nil)
+		casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+				entries, err := os.ReadDir(spoolDirectory)
+				require.NoError(t, err)
+				require.Len(t, entries, 1)
+
+				data, err := b.ToByteSlice(10)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello"), data)
+				return nil
+			})
+
+		response, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(response.BlobDigest, helloDigest.GetProto()))
+
+		// The spool file should be removed once the buffer is consumed.
+		entries, err := os.ReadDir(spoolDirectory)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+
+	t.Run("ChecksumMismatch", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{
+					Name:  "checksum.sri",
+					Value: "sha256-GF+NsyJx/iX1Yab8k4suJkMG7DBO2lGAB9F2SCY4GWk=",
+				},
+			},
+		}
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(strings.NewReader("Goodbye")),
+			ContentLength: -1,
+		}, nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+
+		entries, err := os.ReadDir(spoolDirectory)
+		require.NoError(t, err)
+		require.Empty(t, entries)
+	})
+}
+
+func TestHTTPFetcherFetchBlobChecksumSri(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "www.example.com"
+	casBlobAccess := mock.NewMockBlobAccess(ctrl)
+	roundTripper := mock.NewMockRoundTripper(ctrl)
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
+	helloDigest := bb_digest.MustNewDigest(
+		"",
+		remoteexecution.DigestFunction_SHA256,
+		"185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		5,
+	)
+	newRequest := func(checksum string) *remoteasset.FetchBlobRequest {
+		return &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{
+					Name:  "checksum.sri",
+					Value: checksum,
+				},
+			},
+		}
+	}
+
+	for name, checksum := range map[string]string{
+		"Sha1":   "sha1-9/+ei3uy4Jtwk1pdeF4MxdnQq/A=",
+		"Sha384": "sha384-NRn+WtLFlu/j4nam81G4/AsD24YXgkkNRfdZjr0Ktf1VIO0QLzjEpeyDTphmgDX8",
+		"Sha512": "sha512-NhX4DJ0pPtdAJof5SyLVjlKbjMeRb4+sf933+9WvTPd309eVp6AKFr9+fz+5Vh7puq5IDan+ehh2nnGIawPzFQ==",
+		// Only one of the checksums needs to match.
+		"MultipleOneMatching": "sha256-AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA= sha384-NRn+WtLFlu/j4nam81G4/AsD24YXgkkNRfdZjr0Ktf1VIO0QLzjEpeyDTphmgDX8",
+		// Unknown algorithms and options should be ignored.
+		"UnknownAlgorithmAndOptions": "md5-XrY7u+Ae7tCTyyK7j1rNww== sha384-NRn+WtLFlu/j4nam81G4/AsD24YXgkkNRfdZjr0Ktf1VIO0QLzjEpeyDTphmgDX8?foo",
+	} {
+		t.Run(name, func(t *testing.T) {
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+				Status:        "200 Success",
+				StatusCode:    200,
+				Body:          io.NopCloser(strings.NewReader("Hello")),
+				ContentLength: 5,
+			}, nil)
+			casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+				func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+					_, err := b.ToByteSlice(10)
+					return err
+				})
+
+			response, err := HTTPFetcher.FetchBlob(ctx, newRequest(checksum))
+			require.NoError(t, err)
+			require.True(t, proto.Equal(response.BlobDigest, helloDigest.GetProto()))
+		})
+	}
+
+	t.Run("NoneMatching", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(strings.NewReader("Goodbye")),
+			ContentLength: 7,
+		}, nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, newRequest("sha1-9/+ei3uy4Jtwk1pdeF4MxdnQq/A= sha512-NhX4DJ0pPtdAJof5SyLVjlKbjMeRb4+sf933+9WvTPd309eVp6AKFr9+fz+5Vh7puq5IDan+ehh2nnGIawPzFQ=="))
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("NoSupportedAlgorithm", func(t *testing.T) {
+		_, err := HTTPFetcher.FetchBlob(ctx, newRequest("md5-XrY7u+Ae7tCTyyK7j1rNww=="))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InvalidLength", func(t *testing.T) {
+		_, err := HTTPFetcher.FetchBlob(ctx, newRequest("sha384-9/+ei3uy4Jtwk1pdeF4MxdnQq/A="))
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
 func TestHTTPFetcherFetchDirectory(t *testing.T) {
 	ctrl, ctx := gomock.WithContext(context.Background(), t)
 
 	uri := "www.example.com"
-	request := &remoteasset.FetchDirectoryRequest{
+	casBlobAccess := mock.NewMockBlobAccess(ctrl)
+	roundTripper := mock.NewMockRoundTripper(ctrl)
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
+
+	// Capture all objects written into the CAS.
+	cas := map[string][]byte{}
+	casBlobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+			data, err := b.ToByteSlice(1024)
+			require.NoError(t, err)
+			cas[digest.GetHashString()] = data
+			return nil
+		}).AnyTimes()
+	getDirectory := func(t *testing.T, digest *remoteexecution.Digest) *remoteexecution.Directory {
+		var directory remoteexecution.Directory
+		require.NoError(t, proto.Unmarshal(cas[digest.Hash], &directory))
+		return &directory
+	}
+	helloDigest := &remoteexecution.Digest{
+		Hash:      "185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		SizeBytes: 5,
+	}
+	scriptDigest := &remoteexecution.Digest{
+		Hash:      "3af71adb278ad4af33c144b78fa1ae708da03b773d98324ae991a7daedb53ca2",
+		SizeBytes: 9,
+	}
+	checkProjectContents := func(t *testing.T, digest *remoteexecution.Digest) {
+		testutil.RequireEqualProto(t, &remoteexecution.Directory{
+			Files: []*remoteexecution.FileNode{
+				{Name: "hello.txt", Digest: helloDigest},
+				{Name: "run.sh", Digest: scriptDigest, IsExecutable: true},
+			},
+			Symlinks: []*remoteexecution.SymlinkNode{
+				{Name: "link", Target: "hello.txt"},
+			},
+		}, getDirectory(t, digest))
+		require.Equal(t, []byte("Hello"), cas[helloDigest.Hash])
+	}
+	checkContents := func(t *testing.T, response *remoteasset.FetchDirectoryResponse) {
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+		root := getDirectory(t, response.RootDirectoryDigest)
+		require.Len(t, root.Directories, 1)
+		require.Equal(t, "project-1.2.3", root.Directories[0].Name)
+		checkProjectContents(t, root.Directories[0].Digest)
+	}
+
+	var tarArchive bytes.Buffer
+	tarWriter := tar.NewWriter(&tarArchive)
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "project-1.2.3/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "project-1.2.3/hello.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 5}))
+	tarWriter.Write([]byte("Hello"))
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "project-1.2.3/run.sh", Typeflag: tar.TypeReg, Mode: 0o755, Size: 9}))
+	tarWriter.Write([]byte("#!/bin/sh"))
+	require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "project-1.2.3/link", Typeflag: tar.TypeSymlink, Linkname: "hello.txt"}))
+	require.NoError(t, tarWriter.Close())
+
+	var tarGzipArchive bytes.Buffer
+	gzipWriter := gzip.NewWriter(&tarGzipArchive)
+	gzipWriter.Write(tarArchive.Bytes())
+	require.NoError(t, gzipWriter.Close())
+
+	var zipArchive bytes.Buffer
+	zipWriter := zip.NewWriter(&zipArchive)
+	for _, entry := range []struct {
+		name     string
+		mode     os.FileMode
+		contents string
+	}{
+		{"project-1.2.3/", os.ModeDir | 0o755, ""},
+		{"project-1.2.3/hello.txt", 0o644, "Hello"},
+		{"project-1.2.3/run.sh", 0o755, "#!/bin/sh"},
+		{"project-1.2.3/link", os.ModeSymlink | 0o777, "hello.txt"},
+	} {
+		header := &zip.FileHeader{Name: entry.name}
+		header.SetMode(entry.mode)
+		w, err := zipWriter.CreateHeader(header)
+		require.NoError(t, err)
+		w.Write([]byte(entry.contents))
+	}
+	require.NoError(t, zipWriter.Close())
+
+	for name, archive := range map[string][]byte{
+		"Tar":   tarArchive.Bytes(),
+		"TarGz": tarGzipArchive.Bytes(),
+		"Zip":   zipArchive.Bytes(),
+	} {
+		t.Run(name+"Sniffed", func(t *testing.T) {
+			roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+				Status:        "200 Success",
+				StatusCode:    200,
+				Body:          io.NopCloser(bytes.NewReader(archive)),
+				ContentLength: int64(len(archive)),
+			}, nil)
+
+			response, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+				InstanceName: "",
+				Uris:         []string{uri},
+			})
+			require.NoError(t, err)
+			checkContents(t, response)
+		})
+	}
+
+	t.Run("ArchiveTypeQualifier", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewReader(tarGzipArchive.Bytes())),
+			ContentLength: int64(tarGzipArchive.Len()),
+		}, nil)
+
+		response, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.archive_type", Value: "tar.gz"},
+			},
+		})
+		require.NoError(t, err)
+		checkContents(t, response)
+	})
+
+	t.Run("WrongArchiveTypeQualifier", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewReader(tarGzipArchive.Bytes())),
+			ContentLength: int64(tarGzipArchive.Len()),
+		}, nil)
+
+		_, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.archive_type", Value: "zip"},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("UnsupportedArchiveTypeQualifier", func(t *testing.T) {
+		_, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.archive_type", Value: "rar"},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("PathEscapingRoot", func(t *testing.T) {
+		var archive bytes.Buffer
+		tarWriter := tar.NewWriter(&archive)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644, Size: 5}))
+		tarWriter.Write([]byte("Hello"))
+		require.NoError(t, tarWriter.Close())
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewReader(archive.Bytes())),
+			ContentLength: int64(archive.Len()),
+		}, nil)
+
+		_, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("StripPrefix", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewReader(tarArchive.Bytes())),
+			ContentLength: int64(tarArchive.Len()),
+		}, nil)
+
+		response, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.strip_prefix", Value: "project-1.2.3/"},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+		checkProjectContents(t, response.RootDirectoryDigest)
+	})
+
+	t.Run("StripPrefixNonExistent", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(bytes.NewReader(tarArchive.Bytes())),
+			ContentLength: int64(tarArchive.Len()),
+		}, nil)
+
+		_, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.strip_prefix", Value: "project-4.5.6"},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("StripPrefixEscapingRoot", func(t *testing.T) {
+		_, err := HTTPFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "bazel.strip_prefix", Value: "../project-1.2.3"},
+			},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestHTTPFetcherFetchBlobRetries(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "www.example.com"
+	request := &remoteasset.FetchBlobRequest{
 		InstanceName: "",
-		Uris:         []string{uri, "www.another.com"},
+		Uris:         []string{uri},
+	}
+	casBlobAccess := mock.NewMockBlobAccess(ctrl)
+	roundTripper := mock.NewMockRoundTripper(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.HTTPRetryPolicy{
+		MaximumAttempts:      3,
+		InitialBackoff:       time.Second,
+		BackoffMultiplier:    2,
+		MaximumBackoff:       time.Minute,
+		RetryableStatusCodes: map[int]struct{}{503: {}},
+		RetryTransportErrors: true,
+	}, 0, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock)
+	helloDigest := bb_digest.MustNewDigest(
+		"",
+		remoteexecution.DigestFunction_SHA256,
+		"185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		5,
+	)
+	expectTimer := func(d time.Duration) {
+		timer := mock.NewMockTimer(ctrl)
+		ch := make(chan time.Time, 1)
+		ch <- time.Unix(0, 0)
+		clock.EXPECT().NewTimer(d).Return(timer, ch)
+	}
+	unavailableResponse := func(header http.Header) *http.Response {
+		return &http.Response{
+			Status:     "503 Service Unavailable",
+			StatusCode: 503,
+			Header:     header,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}
+	}
+	successResponse := func() *http.Response {
+		return &http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Body:          io.NopCloser(strings.NewReader("Hello")),
+			ContentLength: 5,
+		}
+	}
+
+	t.Run("SuccessAfterRetries", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(nil, status.Error(codes.Unavailable, "Connection reset by peer"))
+		expectTimer(time.Second)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{}), nil)
+		expectTimer(2 * time.Second)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(successResponse(), nil)
+		casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return nil
+			})
+
+		response, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+		require.True(t, proto.Equal(response.BlobDigest, helloDigest.GetProto()))
+	})
+
+	t.Run("RetryAfter", func(t *testing.T) {
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{"Retry-After": []string{"30"}}), nil)
+		expectTimer(30 * time.Second)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(successResponse(), nil)
+		casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+				b.Discard()
+				return nil
+			})
+
+		response, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+	})
+
+	t.Run("AttemptsExhausted", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{}), nil)
+		expectTimer(time.Second)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{}), nil)
+		expectTimer(2 * time.Second)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{}), nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("NotRetryable", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:     "404 Not Found",
+			StatusCode: 404,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		deadline := time.Now().Add(time.Hour)
+		ctxWithDeadline, cancel := context.WithDeadline(ctx, deadline)
+		defer cancel()
+		clock.EXPECT().Now().Return(deadline.Add(-10 * time.Second)).Times(2)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(unavailableResponse(http.Header{"Retry-After": []string{"30"}}), nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctxWithDeadline, request)
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestHTTPFetcherFetchBlobResume(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "www.example.com"
+	casBlobAccess := mock.NewMockBlobAccess(ctrl)
+	roundTripper := mock.NewMockRoundTripper(ctrl)
+	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 1, fetch.NoHTTPCredentials, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
+	helloDigest := bb_digest.MustNewDigest(
+		"",
+		remoteexecution.DigestFunction_SHA256,
+		"185f8db32271fe25f561a6fc938b2e264306ec304eda518007d1764826381969",
+		5,
+	)
+	interruptedResponse := func(header http.Header) *http.Response {
+		return &http.Response{
+			Status:        "200 Success",
+			StatusCode:    200,
+			Header:        header,
+			Body:          io.NopCloser(io.MultiReader(strings.NewReader("Hel"), iotest.ErrReader(errors.New("connection reset by peer")))),
+			ContentLength: 5,
+		}
 	}
+	remainderResponse := func() *http.Response {
+		return &http.Response{
+			Status:        "206 Partial Content",
+			StatusCode:    206,
+			Header:        http.Header{"Content-Range": []string{"bytes 3-4/5"}},
+			Body:          io.NopCloser(strings.NewReader("lo")),
+			ContentLength: 2,
+		}
+	}
+	expectPut := func(t *testing.T) {
+		casBlobAccess.EXPECT().Put(ctx, helloDigest, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest bb_digest.Digest, b buffer.Buffer) error {
+				data, err := b.ToByteSlice(1024)
+				require.NoError(t, err)
+				require.Equal(t, []byte("Hello"), data)
+				return nil
+			})
+	}
+
+	t.Run("ResumeWithChecksum", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(interruptedResponse(http.Header{
+			"Accept-Ranges": []string{"bytes"},
+		}), nil)
+		roundTripper.EXPECT().RoundTrip(&headerMatcher{headers: map[string]string{
+			"Range": "bytes=3-",
+		}}).Return(remainderResponse(), nil)
+		expectPut(t)
+
+		response, err := HTTPFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+			Qualifiers: []*remoteasset.Qualifier{
+				{Name: "checksum.sri", Value: "sha256-GF+NsyJx/iX1Yab8k4suJkMG7DBO2lGAB9F2SCY4GWk="},
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+		require.True(t, proto.Equal(response.BlobDigest, helloDigest.GetProto()))
+	})
+
+	t.Run("ResumeWithETag", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(interruptedResponse(http.Header{
+			"Accept-Ranges": []string{"bytes"},
+			"Etag":          []string{"\"v1\""},
+		}), nil)
+		roundTripper.EXPECT().RoundTrip(&headerMatcher{headers: map[string]string{
+			"Range":    "bytes=3-",
+			"If-Range": "\"v1\"",
+		}}).Return(remainderResponse(), nil)
+		expectPut(t)
+
+		response, err := HTTPFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.OK), response.Status.Code)
+		require.True(t, proto.Equal(response.BlobDigest, helloDigest.GetProto()))
+	})
+
+	t.Run("RangeNotHonored", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(interruptedResponse(http.Header{
+			"Accept-Ranges": []string{"bytes"},
+			"Etag":          []string{"\"v1\""},
+		}), nil)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(&http.Response{
+			Status:     "200 Success",
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Hello")),
+		}, nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+		})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("RangesNotSupported", func(t *testing.T) {
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).Return(interruptedResponse(http.Header{
+			"Etag": []string{"\"v1\""},
+		}), nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "",
+			Uris:         []string{uri},
+		})
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestHTTPFetcherCredentials(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	uri := "https://example.com/hello.txt"
 	casBlobAccess := mock.NewMockBlobAccess(ctrl)
 	roundTripper := mock.NewMockRoundTripper(ctrl)
-	HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess)
-	_, err := HTTPFetcher.FetchDirectory(ctx, request)
-	require.NotNil(t, err)
-	require.Equal(t, status.Code(err), codes.PermissionDenied)
+	credentialProvider := fetch.NewStaticHTTPCredentialProvider(http.Header{
+		"Authorization": []string{"Bearer server"},
+		"X-Server":      []string{"1"},
+	})
+	request := &remoteasset.FetchBlobRequest{
+		InstanceName: "",
+		Uris:         []string{uri},
+		Qualifiers: []*remoteasset.Qualifier{
+			{
+				Name:  "bazel.auth_headers",
+				Value: `{ "https://example.com/hello.txt": { "Authorization": "Bearer client", "X-Client": "1" } }`,
+			},
+		},
+	}
+	expectRequest := func(headers map[string]string) {
+		roundTripper.EXPECT().RoundTrip(&headerMatcher{headers: headers}).Return(&http.Response{
+			Status:     "200 Success",
+			StatusCode: 200,
+			Body:       io.NopCloser(strings.NewReader("Hello")),
+		}, nil)
+		casBlobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).Return(nil)
+	}
+
+	t.Run("ClientOverridesServer", func(t *testing.T) {
+		HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, credentialProvider, fetch.HTTPCredentialPrecedenceClient, clock.SystemClock)
+		expectRequest(map[string]string{"Authorization": "Bearer client", "X-Client": "1", "X-Server": "1"})
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("ServerOverridesClient", func(t *testing.T) {
+		HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, credentialProvider, fetch.HTTPCredentialPrecedenceServer, clock.SystemClock)
+		expectRequest(map[string]string{"Authorization": "Bearer server", "X-Client": "1", "X-Server": "1"})
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+	})
+
+	t.Run("ServerExclusive", func(t *testing.T) {
+		HTTPFetcher := fetch.NewHTTPFetcher(&http.Client{Transport: roundTripper}, casBlobAccess, 1024*1024, "", fetch.NoHTTPRetries, 0, credentialProvider, fetch.HTTPCredentialPrecedenceServerExclusive, clock.SystemClock)
+		roundTripper.EXPECT().RoundTrip(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+			require.Equal(t, "Bearer server", req.Header.Get("Authorization"))
+			require.Empty(t, req.Header.Get("X-Client"))
+			return &http.Response{
+				Status:     "200 Success",
+				StatusCode: 200,
+				Body:       io.NopCloser(strings.NewReader("Hello")),
+			}, nil
+		})
+		casBlobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := HTTPFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+	})
 }
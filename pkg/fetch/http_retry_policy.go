@@ -0,0 +1,67 @@
+package fetch
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HTTPRetryPolicy controls how the HTTP fetcher retries requests that
+// fail transiently.
+type HTTPRetryPolicy struct {
+	// The maximum number of attempts made per URI, including the
+	// initial attempt.
+	MaximumAttempts int
+	// The amount of time to wait before the first retry, which is
+	// multiplied by BackoffMultiplier after every attempt.
+	InitialBackoff    time.Duration
+	BackoffMultiplier float64
+	// Upper bound on the computed backoff. Zero means unbounded.
+	MaximumBackoff time.Duration
+	// HTTP status codes for which requests are retried.
+	RetryableStatusCodes map[int]struct{}
+	// Whether requests that fail without a response are retried.
+	RetryTransportErrors bool
+}
+
+// NoHTTPRetries is a HTTPRetryPolicy that attempts every request
+// exactly once.
+var NoHTTPRetries = HTTPRetryPolicy{MaximumAttempts: 1}
+
+func (rp *HTTPRetryPolicy) isRetryableStatusCode(statusCode int) bool {
+	_, ok := rp.RetryableStatusCodes[statusCode]
+	return ok
+}
+
+// getBackoff returns the amount of time to wait after a given number
+// of failed attempts.
+func (rp *HTTPRetryPolicy) getBackoff(failedAttempts int) time.Duration {
+	multiplier := math.Max(rp.BackoffMultiplier, 1)
+	backoff := float64(rp.InitialBackoff) * math.Pow(multiplier, float64(failedAttempts-1))
+	if rp.MaximumBackoff > 0 && backoff > float64(rp.MaximumBackoff) {
+		return rp.MaximumBackoff
+	}
+	if backoff > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return time.Duration(backoff)
+}
+
+// parseRetryAfter parses the value of a 'Retry-After' response
+// header, which contains either a number of seconds or a HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,176 @@
+package fetch
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	protostatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	negativeCachingFetcherPrometheusMetrics sync.Once
+
+	negativeCachingFetcherHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "remote_asset",
+			Name:      "negative_caching_fetcher_hits_total",
+			Help:      "Number of fetches that failed due to a previously observed failure being cached.",
+		},
+		[]string{"operation"})
+)
+
+// negativeCacheEntry is a failure that is remembered by
+// negativeCachingFetcher.
+type negativeCacheEntry struct {
+	key       string
+	err       error
+	createdAt time.Time
+	expiresAt time.Time
+}
+
+type negativeCachingFetcher struct {
+	fetcher  Fetcher
+	clock    clock.Clock
+	duration time.Duration
+
+	fetchBlobHits      prometheus.Counter
+	fetchDirectoryHits prometheus.Counter
+
+	lock    sync.Mutex
+	entries map[string]*list.Element
+	// All entries, ordered by expiration time.
+	expirationQueue list.List
+}
+
+// NewNegativeCachingFetcher creates a decorator for Fetcher that
+// remembers fetches that failed with NOT_FOUND for a given duration.
+// This prevents repeated attempts to download assets that do not
+// exist upstream, or whose contents do not match the checksum provided
+// by the client, as fetchers report such failures as NOT_FOUND as
+// well.
+//
+// Failures are cached in memory, keyed by the instance name, URIs and
+// qualifiers of the request. Requests whose oldest_content_accepted is
+// later than the time at which the failure occurred bypass the cache.
+func NewNegativeCachingFetcher(fetcher Fetcher, clock clock.Clock, duration time.Duration) Fetcher {
+	negativeCachingFetcherPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(negativeCachingFetcherHits)
+	})
+
+	return &negativeCachingFetcher{
+		fetcher:  fetcher,
+		clock:    clock,
+		duration: duration,
+
+		fetchBlobHits:      negativeCachingFetcherHits.WithLabelValues("FetchBlob"),
+		fetchDirectoryHits: negativeCachingFetcherHits.WithLabelValues("FetchDirectory"),
+
+		entries: map[string]*list.Element{},
+	}
+}
+
+// lookup returns the failure that is cached for a request, if any.
+func (nf *negativeCachingFetcher) lookup(key string, oldestContentAccepted *timestamppb.Timestamp, now time.Time) error {
+	nf.lock.Lock()
+	defer nf.lock.Unlock()
+
+	element, ok := nf.entries[key]
+	if !ok {
+		return nil
+	}
+	entry := element.Value.(*negativeCacheEntry)
+	if !now.Before(entry.expiresAt) {
+		return nil
+	}
+	if oldestContentAccepted != nil && entry.createdAt.Before(oldestContentAccepted.AsTime()) {
+		return nil
+	}
+	return entry.err
+}
+
+// update stores the outcome of a fetch. Failures with code NOT_FOUND
+// are cached, while any other outcome causes a cached failure to be
+// discarded. Fetches that were interrupted because the caller went
+// away should not be passed to this function, as fetchers may also
+// report those as NOT_FOUND.
+func (nf *negativeCachingFetcher) update(key string, responseStatus *protostatus.Status, err error, now time.Time) {
+	if err == nil && responseStatus.GetCode() != int32(codes.OK) {
+		err = status.ErrorProto(responseStatus)
+	}
+
+	nf.lock.Lock()
+	defer nf.lock.Unlock()
+
+	// Remove entries that have expired.
+	for element := nf.expirationQueue.Front(); element != nil; element = nf.expirationQueue.Front() {
+		entry := element.Value.(*negativeCacheEntry)
+		if now.Before(entry.expiresAt) {
+			break
+		}
+		nf.expirationQueue.Remove(element)
+		delete(nf.entries, entry.key)
+	}
+
+	if element, ok := nf.entries[key]; ok {
+		nf.expirationQueue.Remove(element)
+		delete(nf.entries, key)
+	}
+	if status.Code(err) == codes.NotFound {
+		nf.entries[key] = nf.expirationQueue.PushBack(&negativeCacheEntry{
+			key:       key,
+			err:       err,
+			createdAt: now,
+			expiresAt: now.Add(nf.duration),
+		})
+	}
+}
+
+func (nf *negativeCachingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+	key, err := getRequestKey("FetchBlob", req.InstanceName, req.Uris, req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+	now := nf.clock.Now()
+	if err := nf.lookup(key, req.OldestContentAccepted, now); err != nil {
+		nf.fetchBlobHits.Inc()
+		return nil, err
+	}
+
+	response, err := nf.fetcher.FetchBlob(ctx, req)
+	if ctx.Err() == nil {
+		nf.update(key, response.GetStatus(), err, now)
+	}
+	return response, err
+}
+
+func (nf *negativeCachingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	key, err := getRequestKey("FetchDirectory", req.InstanceName, req.Uris, req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
+	now := nf.clock.Now()
+	if err := nf.lookup(key, req.OldestContentAccepted, now); err != nil {
+		nf.fetchDirectoryHits.Inc()
+		return nil, err
+	}
+
+	response, err := nf.fetcher.FetchDirectory(ctx, req)
+	if ctx.Err() == nil {
+		nf.update(key, response.GetStatus(), err, now)
+	}
+	return response, err
+}
+
+func (nf *negativeCachingFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
+	return nf.fetcher.CheckQualifiers(qualifiers)
+}
@@ -0,0 +1,106 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestNegativeCachingFetcherFetchBlob(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	negativeCachingFetcher := fetch.NewNegativeCachingFetcher(baseFetcher, clock, time.Minute)
+	notFoundErr := status.Error(codes.NotFound, "Unable to download blob from any provided URI")
+	response := &remoteasset.FetchBlobResponse{
+		Status:     status.New(codes.OK, "Blob fetched successfully!").Proto(),
+		Uri:        "https://example.com/a.tar.gz",
+		BlobDigest: &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123},
+	}
+
+	t.Run("CachedFailure", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/a.tar.gz"},
+		}
+
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(nil, notFoundErr)
+		_, err := negativeCachingFetcher.FetchBlob(ctx, request)
+		testutil.RequireEqualStatus(t, notFoundErr, err)
+
+		// Subsequent requests should fail without calling into
+		// the backend.
+		clock.EXPECT().Now().Return(time.Unix(1059, 0))
+		_, err = negativeCachingFetcher.FetchBlob(ctx, request)
+		testutil.RequireEqualStatus(t, notFoundErr, err)
+
+		// Once expired, the backend should be called again.
+		clock.EXPECT().Now().Return(time.Unix(1060, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(response, nil)
+		r, err := negativeCachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+	})
+
+	t.Run("OldestContentAccepted", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/b.tar.gz"},
+		}
+
+		clock.EXPECT().Now().Return(time.Unix(1000, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(&remoteasset.FetchBlobResponse{
+			Status: status.Convert(notFoundErr).Proto(),
+		}, nil)
+		_, err := negativeCachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+
+		// Failures that occurred before oldest_content_accepted
+		// should be ignored. Once the fetch succeeds, the failure
+		// should no longer be returned to other clients either.
+		requestWithOldestContentAccepted := &remoteasset.FetchBlobRequest{
+			InstanceName:          "instance",
+			Uris:                  []string{"https://example.com/b.tar.gz"},
+			OldestContentAccepted: timestamppb.New(time.Unix(1010, 0)),
+		}
+		clock.EXPECT().Now().Return(time.Unix(1020, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, requestWithOldestContentAccepted).Return(response, nil)
+		r, err := negativeCachingFetcher.FetchBlob(ctx, requestWithOldestContentAccepted)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+
+		clock.EXPECT().Now().Return(time.Unix(1030, 0))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(response, nil)
+		r, err = negativeCachingFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, response, r)
+	})
+
+	t.Run("OtherFailure", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/c.tar.gz"},
+		}
+		internalErr := status.Error(codes.Internal, "Failed to place blob into CAS")
+
+		clock.EXPECT().Now().Return(time.Unix(1000, 0)).Times(2)
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(nil, internalErr).Times(2)
+		for i := 0; i < 2; i++ {
+			_, err := negativeCachingFetcher.FetchBlob(ctx, request)
+			testutil.RequireEqualStatus(t, internalErr, err)
+		}
+	})
+}
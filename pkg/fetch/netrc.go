@@ -0,0 +1,168 @@
+package fetch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// netrcEntry contains the credentials of a 'machine' or 'default'
+// entry in a .netrc file.
+type netrcEntry struct {
+	login    string
+	password string
+}
+
+// netrc contains the parsed contents of a .netrc file.
+type netrc struct {
+	machines     map[string]netrcEntry
+	defaultEntry *netrcEntry
+}
+
+// parseNetrc parses the contents of a .netrc file. Macro definitions
+// and 'account' tokens are ignored.
+func parseNetrc(contents []byte) (*netrc, error) {
+	n := &netrc{
+		machines: map[string]netrcEntry{},
+	}
+	var current *netrcEntry
+	var currentMachine string
+	finishEntry := func() {
+		if current != nil {
+			if currentMachine == "" {
+				n.defaultEntry = current
+			} else if _, ok := n.machines[currentMachine]; !ok {
+				n.machines[currentMachine] = *current
+			}
+		}
+		current = nil
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	inMacro := false
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inMacro {
+			// Macro definitions are terminated by an empty line.
+			inMacro = strings.TrimSpace(line) != ""
+			continue
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		tokens := strings.Fields(line)
+		for i := 0; i < len(tokens); i++ {
+			token := tokens[i]
+			switch token {
+			case "machine", "login", "password", "account", "macdef":
+				if i+1 >= len(tokens) {
+					return nil, status.Errorf(codes.InvalidArgument, "Token %#v is not followed by a value", token)
+				}
+				i++
+				value := tokens[i]
+				switch token {
+				case "machine":
+					finishEntry()
+					current = &netrcEntry{}
+					currentMachine = value
+				case "login", "password":
+					if current == nil {
+						return nil, status.Errorf(codes.InvalidArgument, "Token %#v is not part of a machine entry", token)
+					}
+					if token == "login" {
+						current.login = value
+					} else {
+						current.password = value
+					}
+				case "macdef":
+					inMacro = true
+					i = len(tokens)
+				}
+			case "default":
+				finishEntry()
+				current = &netrcEntry{}
+				currentMachine = ""
+			default:
+				return nil, status.Errorf(codes.InvalidArgument, "Unknown token %#v", token)
+			}
+		}
+	}
+	finishEntry()
+	return n, nil
+}
+
+func (n *netrc) lookup(host string) *netrcEntry {
+	if entry, ok := n.machines[host]; ok {
+		return &entry
+	}
+	return n.defaultEntry
+}
+
+type netrcHTTPCredentialProvider struct {
+	file *reloadingFile
+
+	lock     sync.Mutex
+	contents []byte
+	netrc    *netrc
+}
+
+// NewNetrcHTTPCredentialProvider creates an HTTPCredentialProvider
+// that provides basic authentication credentials based on the host of
+// a URI, using the login and password stored in a .netrc file. The
+// file is read again whenever it is modified.
+func NewNetrcHTTPCredentialProvider(path string) HTTPCredentialProvider {
+	return &netrcHTTPCredentialProvider{
+		file: newReloadingFile(path),
+	}
+}
+
+func (cp *netrcHTTPCredentialProvider) getNetrc() (*netrc, error) {
+	contents, err := cp.file.Read()
+	if err != nil {
+		return nil, err
+	}
+
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	if cp.netrc == nil || !bytes.Equal(cp.contents, contents) {
+		n, err := parseNetrc(contents)
+		if err != nil {
+			return nil, util.StatusWrapf(err, "Failed to parse %#v", cp.file.path)
+		}
+		cp.contents = contents
+		cp.netrc = n
+	}
+	return cp.netrc, nil
+}
+
+func (cp *netrcHTTPCredentialProvider) GetHeaders(ctx context.Context, uri string) (http.Header, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, nil
+	}
+	n, err := cp.getNetrc()
+	if err != nil {
+		return nil, err
+	}
+	entry := n.lookup(parsed.Hostname())
+	if entry == nil {
+		return nil, nil
+	}
+	return newBasicAuthHeader(entry.login, entry.password), nil
+}
+
+// newBasicAuthHeader creates a header for performing HTTP basic
+// authentication.
+func newBasicAuthHeader(username, password string) http.Header {
+	req := http.Request{Header: http.Header{}}
+	req.SetBasicAuth(username, password)
+	return req.Header
+}
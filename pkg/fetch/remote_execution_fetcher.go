@@ -11,6 +11,7 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -127,7 +128,10 @@ func (rf *remoteExecutionFetcher) fetchCommon(ctx context.Context, req *remoteas
 		}
 		return actionResult, uri, command.OutputPaths[0], nil
 	}
-	return nil, "", "", status.Errorf(codes.NotFound, "Unable to download blob from any of the provided URIs")
+	// The exit code of the action does not indicate whether the
+	// asset exists, so don't report NOT_FOUND, as such failures are
+	// cached by NegativeCachingFetcher.
+	return nil, "", "", status.Errorf(codes.Unavailable, "Unable to download blob from any of the provided URIs")
 }
 
 func (rf *remoteExecutionFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
@@ -163,6 +167,10 @@ func (rf *remoteExecutionFetcher) FetchBlob(ctx context.Context, req *remoteasse
 }
 
 func (rf *remoteExecutionFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	stripPrefix, err := getStripPrefix(req.Qualifiers)
+	if err != nil {
+		return nil, err
+	}
 	blobReq := &remoteasset.FetchBlobRequest{
 		InstanceName: req.InstanceName,
 		Uris:         req.Uris,
@@ -212,6 +220,10 @@ func (rf *remoteExecutionFetcher) FetchDirectory(ctx context.Context, req *remot
 	if err != nil {
 		return nil, err
 	}
+	resultDigest, err := lookupTreeSubdirectory(tree.(*remoteexecution.Tree), stripPrefix)
+	if err != nil {
+		return nil, util.StatusWrap(err, "Failed to strip prefix")
+	}
 	bbRootDigest, err := digestFunction.NewDigestFromProto(rootDigest)
 	if err != nil {
 		return nil, err
@@ -238,10 +250,10 @@ func (rf *remoteExecutionFetcher) FetchDirectory(ctx context.Context, req *remot
 		Status:              status.New(codes.OK, "Directory fetched successfully!").Proto(),
 		Uri:                 uri,
 		Qualifiers:          req.Qualifiers,
-		RootDirectoryDigest: rootDigest,
+		RootDirectoryDigest: resultDigest,
 	}, nil
 }
 
 func (rf *remoteExecutionFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
-	return qualifier.Difference(qualifiers, qualifier.NewSet([]string{"resource_type", "vcs.branch", "vcs.commit", "auth.basic.username", "auth.basic.password", "checksum.sri"}))
+	return qualifier.Difference(qualifiers, qualifier.NewSet([]string{"resource_type", "vcs.branch", "vcs.commit", "auth.basic.username", "auth.basic.password", "checksum.sri", "bazel.strip_prefix"}))
 }
@@ -0,0 +1,121 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// makeResumable replaces the body of a HTTP response by one that
+// resumes the download using HTTP range requests when reading fails.
+// This is only done if the server announces support for range
+// requests, and if resuming cannot silently yield different content.
+// This is either because the response carries a validator that can be
+// provided through 'If-Range', or because the content is validated
+// against a checksum afterwards.
+func (hf *httpFetcher) makeResumable(ctx context.Context, uri string, auth *AuthHeaders, resp *http.Response, hasChecksum bool) {
+	if hf.maximumResumeAttempts <= 0 || resp.Header.Get("Accept-Ranges") != "bytes" {
+		return
+	}
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		// Weak entity tags cannot be used with 'If-Range'.
+		validator = resp.Header.Get("Last-Modified")
+	}
+	if validator == "" && !hasChecksum {
+		return
+	}
+	resp.Body = &resumableBody{
+		ctx:          ctx,
+		fetcher:      hf,
+		uri:          uri,
+		auth:         auth,
+		validator:    validator,
+		body:         resp.Body,
+		attemptsLeft: hf.maximumResumeAttempts,
+	}
+}
+
+// resumableBody is an io.ReadCloser for a HTTP response body that
+// requests the remainder of the body from the server if reading fails.
+type resumableBody struct {
+	ctx       context.Context
+	fetcher   *httpFetcher
+	uri       string
+	auth      *AuthHeaders
+	validator string
+
+	body         io.ReadCloser
+	offsetBytes  int64
+	attemptsLeft int
+}
+
+func (rb *resumableBody) Read(p []byte) (int, error) {
+	for {
+		n, err := rb.body.Read(p)
+		rb.offsetBytes += int64(n)
+		if err == nil || err == io.EOF {
+			return n, err
+		}
+		if resumeErr := rb.resume(err); resumeErr != nil {
+			return n, resumeErr
+		}
+		if n > 0 {
+			return n, nil
+		}
+	}
+}
+
+func (rb *resumableBody) resume(readErr error) error {
+	if rb.attemptsLeft <= 0 || rb.ctx.Err() != nil {
+		return readErr
+	}
+	rb.attemptsLeft--
+	rb.body.Close()
+	rb.body = http.NoBody
+
+	log.Printf("Reading response body of URI %s failed after %d bytes, resuming download: %v", rb.uri, rb.offsetBytes, readErr)
+	header := http.Header{}
+	header.Set("Range", fmt.Sprintf("bytes=%d-", rb.offsetBytes))
+	if rb.validator != "" {
+		header.Set("If-Range", rb.validator)
+	}
+	resp, err := rb.fetcher.doRequest(rb.ctx, rb.uri, rb.auth, header, http.StatusPartialContent)
+	if err != nil {
+		return util.StatusWrapf(err, "Failed to resume download after %d bytes", rb.offsetBytes)
+	}
+	contentRange := resp.Header.Get("Content-Range")
+	if start, ok := parseContentRangeStart(contentRange); !ok || start != rb.offsetBytes {
+		resp.Body.Close()
+		return status.Errorf(codes.Unavailable, "Server responded with range %#v when resuming download after %d bytes", contentRange, rb.offsetBytes)
+	}
+	rb.body = resp.Body
+	return nil
+}
+
+func (rb *resumableBody) Close() error {
+	return rb.body.Close()
+}
+
+// parseContentRangeStart returns the offset of the first byte
+// contained in a 'Content-Range' response header.
+func parseContentRangeStart(value string) (int64, bool) {
+	byteRange, ok := strings.CutPrefix(value, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(byteRange, "-")
+	if !ok {
+		return 0, false
+	}
+	offset, err := strconv.ParseInt(start, 10, 64)
+	return offset, err == nil
+}
@@ -0,0 +1,64 @@
+package fetch
+
+import (
+	"strings"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// getStripPrefix returns the path components of the subdirectory at
+// which the result of FetchDirectory should be rooted, as requested
+// through the 'bazel.strip_prefix' qualifier.
+func getStripPrefix(qualifiers []*remoteasset.Qualifier) ([]string, error) {
+	for _, qualifier := range qualifiers {
+		if qualifier.Name == "bazel.strip_prefix" {
+			return splitArchivePath(qualifier.Value)
+		}
+	}
+	return nil, nil
+}
+
+// lookupTreeSubdirectory walks a REv2 Tree, returning the digest of the
+// directory at a given path relative to the root of the tree.
+func lookupTreeSubdirectory(tree *remoteexecution.Tree, components []string) (*remoteexecution.Digest, error) {
+	rootDigest, err := storage.ProtoToDigest(tree.Root)
+	if err != nil {
+		return nil, err
+	}
+	if len(components) == 0 {
+		return rootDigest, nil
+	}
+
+	children := make(map[string]*remoteexecution.Directory, len(tree.Children))
+	for _, child := range tree.Children {
+		childDigest, err := storage.ProtoToDigest(child)
+		if err != nil {
+			return nil, err
+		}
+		children[childDigest.Hash] = child
+	}
+
+	directory := tree.Root
+	var directoryDigest *remoteexecution.Digest
+	for i, component := range components {
+		directoryDigest = nil
+		for _, node := range directory.Directories {
+			if node.Name == component {
+				directoryDigest = node.Digest
+				break
+			}
+		}
+		if directoryDigest == nil {
+			return nil, status.Errorf(codes.NotFound, "Directory %#v does not exist", strings.Join(components[:i+1], "/"))
+		}
+		var ok bool
+		if directory, ok = children[directoryDigest.Hash]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "Tree does not contain directory %#v", strings.Join(components[:i+1], "/"))
+		}
+	}
+	return directoryDigest, nil
+}
@@ -0,0 +1,102 @@
+package fetch
+
+import (
+	"context"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+type timeoutFetcher struct {
+	fetcher        Fetcher
+	clock          clock.Clock
+	defaultTimeout time.Duration
+	maximumTimeout time.Duration
+}
+
+// NewTimeoutFetcher creates a decorator for Fetcher that bounds the
+// amount of time spent fetching an asset, based on the timeout
+// provided in the request. If the request does not contain a timeout,
+// defaultTimeout is used instead. Timeouts are capped to
+// maximumTimeout. A value of zero for either of these disables the
+// respective behaviour.
+func NewTimeoutFetcher(fetcher Fetcher, clock clock.Clock, defaultTimeout, maximumTimeout time.Duration) Fetcher {
+	return &timeoutFetcher{
+		fetcher:        fetcher,
+		clock:          clock,
+		defaultTimeout: defaultTimeout,
+		maximumTimeout: maximumTimeout,
+	}
+}
+
+// getTimeout computes the timeout to apply to a request, returning
+// zero if the request may run indefinitely.
+func (tf *timeoutFetcher) getTimeout(requestTimeout *durationpb.Duration) (time.Duration, error) {
+	timeout := tf.defaultTimeout
+	if requestTimeout != nil {
+		if err := requestTimeout.CheckValid(); err != nil {
+			return 0, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid timeout")
+		}
+		if d := requestTimeout.AsDuration(); d < 0 {
+			return 0, status.Errorf(codes.InvalidArgument, "Timeout %s is negative", d)
+		} else if d > 0 {
+			timeout = d
+		}
+	}
+	if tf.maximumTimeout > 0 && (timeout == 0 || timeout > tf.maximumTimeout) {
+		timeout = tf.maximumTimeout
+	}
+	return timeout, nil
+}
+
+func (tf *timeoutFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+	timeout, err := tf.getTimeout(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		return tf.fetcher.FetchBlob(ctx, req)
+	}
+
+	ctxWithTimeout, cancel := tf.clock.NewContextWithTimeout(ctx, timeout)
+	defer cancel()
+	response, err := tf.fetcher.FetchBlob(ctxWithTimeout, req)
+	if err != nil && ctx.Err() == nil && ctxWithTimeout.Err() == context.DeadlineExceeded {
+		return &remoteasset.FetchBlobResponse{
+			Status:     status.Newf(codes.DeadlineExceeded, "Failed to fetch blob within %s", timeout).Proto(),
+			Qualifiers: req.Qualifiers,
+		}, nil
+	}
+	return response, err
+}
+
+func (tf *timeoutFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	timeout, err := tf.getTimeout(req.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	if timeout == 0 {
+		return tf.fetcher.FetchDirectory(ctx, req)
+	}
+
+	ctxWithTimeout, cancel := tf.clock.NewContextWithTimeout(ctx, timeout)
+	defer cancel()
+	response, err := tf.fetcher.FetchDirectory(ctxWithTimeout, req)
+	if err != nil && ctx.Err() == nil && ctxWithTimeout.Err() == context.DeadlineExceeded {
+		return &remoteasset.FetchDirectoryResponse{
+			Status:     status.Newf(codes.DeadlineExceeded, "Failed to fetch directory within %s", timeout).Proto(),
+			Qualifiers: req.Qualifiers,
+		}, nil
+	}
+	return response, err
+}
+
+func (tf *timeoutFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
+	return tf.fetcher.CheckQualifiers(qualifiers)
+}
@@ -0,0 +1,122 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func TestTimeoutFetcherFetchBlob(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	clock := mock.NewMockClock(ctrl)
+	timeoutFetcher := fetch.NewTimeoutFetcher(baseFetcher, clock, time.Minute, time.Hour)
+
+	successResponse := &remoteasset.FetchBlobResponse{
+		Status: status.New(codes.OK, "Success!").Proto(),
+		Uri:    "www.example.com",
+	}
+
+	t.Run("DefaultTimeout", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			Uris: []string{"www.example.com"},
+		}
+		clock.EXPECT().NewContextWithTimeout(ctx, time.Minute).Return(ctx, context.CancelFunc(func() {}))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(successResponse, nil)
+
+		response, err := timeoutFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, successResponse, response)
+	})
+
+	t.Run("RequestTimeout", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			Uris:    []string{"www.example.com"},
+			Timeout: durationpb.New(30 * time.Second),
+		}
+		clock.EXPECT().NewContextWithTimeout(ctx, 30*time.Second).Return(ctx, context.CancelFunc(func() {}))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(successResponse, nil)
+
+		response, err := timeoutFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, successResponse, response)
+	})
+
+	t.Run("RequestTimeoutCapped", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			Uris:    []string{"www.example.com"},
+			Timeout: durationpb.New(24 * time.Hour),
+		}
+		clock.EXPECT().NewContextWithTimeout(ctx, time.Hour).Return(ctx, context.CancelFunc(func() {}))
+		baseFetcher.EXPECT().FetchBlob(ctx, request).Return(successResponse, nil)
+
+		response, err := timeoutFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, successResponse, response)
+	})
+
+	t.Run("NegativeTimeout", func(t *testing.T) {
+		_, err := timeoutFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			Uris:    []string{"www.example.com"},
+			Timeout: durationpb.New(-time.Second),
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		request := &remoteasset.FetchBlobRequest{
+			Uris: []string{"www.example.com"},
+		}
+		expiredCtx, cancel := context.WithDeadline(ctx, time.Unix(0, 0))
+		defer cancel()
+		clock.EXPECT().NewContextWithTimeout(ctx, time.Minute).Return(expiredCtx, cancel)
+		baseFetcher.EXPECT().FetchBlob(expiredCtx, request).Return(nil, status.Error(codes.Unavailable, "Connection reset"))
+
+		response, err := timeoutFetcher.FetchBlob(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.DeadlineExceeded), response.Status.Code)
+	})
+}
+
+func TestTimeoutFetcherFetchDirectory(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	clock := mock.NewMockClock(ctrl)
+
+	t.Run("NoTimeout", func(t *testing.T) {
+		timeoutFetcher := fetch.NewTimeoutFetcher(baseFetcher, clock, 0, 0)
+		request := &remoteasset.FetchDirectoryRequest{
+			Uris: []string{"www.example.com"},
+		}
+		baseFetcher.EXPECT().FetchDirectory(ctx, request).Return(nil, status.Error(codes.NotFound, "Not found"))
+
+		_, err := timeoutFetcher.FetchDirectory(ctx, request)
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("DeadlineExceeded", func(t *testing.T) {
+		timeoutFetcher := fetch.NewTimeoutFetcher(baseFetcher, clock, 0, time.Hour)
+		request := &remoteasset.FetchDirectoryRequest{
+			Uris: []string{"www.example.com"},
+		}
+		expiredCtx, cancel := context.WithDeadline(ctx, time.Unix(0, 0))
+		defer cancel()
+		clock.EXPECT().NewContextWithTimeout(ctx, time.Hour).Return(expiredCtx, cancel)
+		baseFetcher.EXPECT().FetchDirectory(expiredCtx, request).Return(nil, status.Error(codes.DeadlineExceeded, "Context deadline exceeded"))
+
+		response, err := timeoutFetcher.FetchDirectory(ctx, request)
+		require.NoError(t, err)
+		require.Equal(t, int32(codes.DeadlineExceeded), response.Status.Code)
+	})
+}
@@ -0,0 +1,165 @@
+package fetch
+
+import (
+	"bufio"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type urlRewrite struct {
+	pattern      *regexp.Regexp
+	replacements []string
+}
+
+// URLRewriter rewrites and filters URIs according to a set of rules
+// written in the syntax of Bazel's --experimental_downloader_config
+// file. The following directives are supported:
+//
+//   - rewrite <regex> <replacement>: Replaces URIs that match the
+//     regular expression, after stripping the scheme. The replacement
+//     may refer to capture groups using $1, $2, etc. If the replacement
+//     does not include a scheme, the scheme of the original URI is
+//     retained. If a pattern is listed multiple times, a URI is
+//     rewritten to all replacements.
+//   - block <host>: Removes URIs whose host is or is a subdomain of
+//     the given host. A host of "*" blocks all URIs.
+//   - allow <host>: Permits URIs whose host is or is a subdomain of the
+//     given host, even if they are blocked.
+//   - all_blocked_message <message>: The message that is returned if
+//     all URIs of a request are blocked.
+//
+// Regular expressions use the RE2 syntax, as opposed to the Java
+// syntax used by Bazel.
+type URLRewriter struct {
+	rewrites          []urlRewrite
+	allowedHosts      []string
+	blockedHosts      []string
+	allBlockedMessage string
+}
+
+// NewURLRewriterFromConfiguration parses a set of rules in the syntax
+// of Bazel's --experimental_downloader_config file.
+func NewURLRewriterFromConfiguration(rules string) (*URLRewriter, error) {
+	ur := &URLRewriter{}
+	scanner := bufio.NewScanner(strings.NewReader(rules))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		switch fields[0] {
+		case "allow", "block":
+			if len(fields) != 2 {
+				return nil, status.Errorf(codes.InvalidArgument, "Line %d: %#v directive requires exactly one host", lineNumber, fields[0])
+			}
+			if fields[0] == "allow" {
+				ur.allowedHosts = append(ur.allowedHosts, fields[1])
+			} else {
+				ur.blockedHosts = append(ur.blockedHosts, fields[1])
+			}
+		case "rewrite":
+			if len(fields) != 3 {
+				return nil, status.Errorf(codes.InvalidArgument, "Line %d: \"rewrite\" directive requires a pattern and a replacement", lineNumber)
+			}
+			if err := ur.addRewrite(lineNumber, fields[1], fields[2]); err != nil {
+				return nil, err
+			}
+		case "all_blocked_message":
+			ur.allBlockedMessage = strings.TrimSpace(strings.TrimPrefix(line, fields[0]))
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "Line %d: Unknown directive %#v", lineNumber, fields[0])
+		}
+	}
+	return ur, nil
+}
+
+func (ur *URLRewriter) addRewrite(lineNumber int, pattern, replacement string) error {
+	for i := range ur.rewrites {
+		if ur.rewrites[i].pattern.String() == "^(?:"+pattern+")$" {
+			ur.rewrites[i].replacements = append(ur.rewrites[i].replacements, replacement)
+			return nil
+		}
+	}
+	compiled, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Line %d: Invalid pattern %#v: %s", lineNumber, pattern, err)
+	}
+	ur.rewrites = append(ur.rewrites, urlRewrite{
+		pattern:      compiled,
+		replacements: []string{replacement},
+	})
+	return nil
+}
+
+// Rewrite applies the rules to a list of URIs. The rewritten URIs are
+// returned, together with a map from each rewritten URI to the URI
+// from which it originated. An error is returned if all URIs are
+// blocked.
+func (ur *URLRewriter) Rewrite(uris []string) ([]string, map[string]string, error) {
+	var rewrittenURIs []string
+	originalURIs := map[string]string{}
+	for _, uri := range uris {
+		for _, rewrittenURI := range ur.rewriteURI(uri) {
+			if _, ok := originalURIs[rewrittenURI]; ok || !ur.isPermitted(rewrittenURI) {
+				continue
+			}
+			rewrittenURIs = append(rewrittenURIs, rewrittenURI)
+			originalURIs[rewrittenURI] = uri
+		}
+	}
+	if len(rewrittenURIs) == 0 && len(uris) > 0 {
+		message := "All URIs were blocked by the URL rewriting rules"
+		if ur.allBlockedMessage != "" {
+			message += ": " + ur.allBlockedMessage
+		}
+		return nil, nil, status.Error(codes.PermissionDenied, message)
+	}
+	return rewrittenURIs, originalURIs, nil
+}
+
+func (ur *URLRewriter) rewriteURI(uri string) []string {
+	scheme, withoutScheme, ok := strings.Cut(uri, "://")
+	if !ok {
+		scheme, withoutScheme = "", uri
+	}
+	var rewrittenURIs []string
+	for _, rewrite := range ur.rewrites {
+		if match := rewrite.pattern.FindStringSubmatchIndex(withoutScheme); match != nil {
+			for _, replacement := range rewrite.replacements {
+				rewrittenURI := string(rewrite.pattern.ExpandString(nil, replacement, withoutScheme, match))
+				if !strings.Contains(rewrittenURI, "://") && scheme != "" {
+					rewrittenURI = scheme + "://" + rewrittenURI
+				}
+				rewrittenURIs = append(rewrittenURIs, rewrittenURI)
+			}
+		}
+	}
+	if len(rewrittenURIs) == 0 {
+		return []string{uri}
+	}
+	return rewrittenURIs
+}
+
+func (ur *URLRewriter) isPermitted(uri string) bool {
+	var host string
+	if parsed, err := url.Parse(uri); err == nil {
+		host = parsed.Hostname()
+	}
+	return matchesHost(host, ur.allowedHosts, false) || !matchesHost(host, ur.blockedHosts, true)
+}
+
+// matchesHost returns whether a host is equal to or a subdomain of
+// any of the hosts in a list.
+func matchesHost(host string, hosts []string, matchWildcard bool) bool {
+	for _, h := range hosts {
+		if (matchWildcard && h == "*") || host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package fetch
+
+import (
+	"context"
+	"encoding/json"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+type urlRewritingFetcher struct {
+	fetcher     Fetcher
+	urlRewriter *URLRewriter
+}
+
+// NewURLRewritingFetcher creates a decorator for Fetcher that rewrites
+// and filters the URIs of requests before they are forwarded to the
+// backend. Headers that the 'bazel.auth_headers' qualifier provides
+// for a URI are applied to the URIs it is rewritten to.
+// Responses report the URI and qualifiers that were provided by the
+// client, so that decorators such as CachingFetcher that are placed
+// in front of this decorator only observe the original request.
+func NewURLRewritingFetcher(fetcher Fetcher, urlRewriter *URLRewriter) Fetcher {
+	return &urlRewritingFetcher{
+		fetcher:     fetcher,
+		urlRewriter: urlRewriter,
+	}
+}
+
+func (rf *urlRewritingFetcher) FetchBlob(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+	rewrittenURIs, originalURIs, err := rf.urlRewriter.Rewrite(req.Uris)
+	if err != nil {
+		return nil, err
+	}
+	rewrittenReq := proto.Clone(req).(*remoteasset.FetchBlobRequest)
+	rewrittenReq.Uris = rewrittenURIs
+	if err := rewriteAuthHeaders(rewrittenReq.Qualifiers, originalURIs); err != nil {
+		return nil, err
+	}
+	response, err := rf.fetcher.FetchBlob(ctx, rewrittenReq)
+	if response != nil {
+		if originalURI, ok := originalURIs[response.Uri]; ok {
+			response.Uri = originalURI
+		}
+		if response.Qualifiers != nil {
+			response.Qualifiers = req.Qualifiers
+		}
+	}
+	return response, err
+}
+
+func (rf *urlRewritingFetcher) FetchDirectory(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+	rewrittenURIs, originalURIs, err := rf.urlRewriter.Rewrite(req.Uris)
+	if err != nil {
+		return nil, err
+	}
+	rewrittenReq := proto.Clone(req).(*remoteasset.FetchDirectoryRequest)
+	rewrittenReq.Uris = rewrittenURIs
+	if err := rewriteAuthHeaders(rewrittenReq.Qualifiers, originalURIs); err != nil {
+		return nil, err
+	}
+	response, err := rf.fetcher.FetchDirectory(ctx, rewrittenReq)
+	if response != nil {
+		if originalURI, ok := originalURIs[response.Uri]; ok {
+			response.Uri = originalURI
+		}
+		if response.Qualifiers != nil {
+			response.Qualifiers = req.Qualifiers
+		}
+	}
+	return response, err
+}
+
+func (rf *urlRewritingFetcher) CheckQualifiers(qualifiers qualifier.Set) qualifier.Set {
+	return rf.fetcher.CheckQualifiers(qualifiers)
+}
+
+// rewriteAuthHeaders rekeys the headers in a 'bazel.auth_headers'
+// qualifier from the URIs provided by the client to the URIs they have
+// been rewritten to. Otherwise, the headers would not be applied.
+func rewriteAuthHeaders(qualifiers []*remoteasset.Qualifier, originalURIs map[string]string) error {
+	for _, q := range qualifiers {
+		if q.Name != "bazel.auth_headers" {
+			continue
+		}
+		authHeaders, err := NewAuthHeadersFromQualifier(q.Value)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid 'bazel.auth_headers' qualifier")
+		}
+		rewrittenAuthHeaders := AuthHeaders{}
+		for rewrittenURI, originalURI := range originalURIs {
+			if headers, ok := (*authHeaders)[originalURI]; ok {
+				rewrittenAuthHeaders[rewrittenURI] = headers
+			}
+		}
+		value, err := json.Marshal(rewrittenAuthHeaders)
+		if err != nil {
+			return util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal rewritten 'bazel.auth_headers' qualifier")
+		}
+		q.Value = string(value)
+	}
+	return nil
+}
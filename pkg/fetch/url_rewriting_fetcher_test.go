@@ -0,0 +1,144 @@
+package fetch_test
+
+import (
+	"context"
+	"testing"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestURLRewritingFetcher(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	urlRewriter, err := fetch.NewURLRewriterFromConfiguration(`
+# Send all GitHub downloads through the mirror.
+rewrite github.com/(.*) https://mirror.example.com/github/$1
+rewrite github.com/(.*) https://mirror2.example.com/github/$1
+# Retain the scheme of the original URI.
+rewrite downloads.example.net/(.*) mirror.example.com/net/$1
+block *
+allow mirror.example.com
+allow example.org
+all_blocked_message Downloads must go through the mirror.
+`)
+	require.NoError(t, err)
+	baseFetcher := mock.NewMockFetcher(ctrl)
+	rewritingFetcher := fetch.NewURLRewritingFetcher(baseFetcher, urlRewriter)
+
+	t.Run("RewriteAndFilter", func(t *testing.T) {
+		baseFetcher.EXPECT().FetchBlob(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+				testutil.RequireEqualProto(t, &remoteasset.FetchBlobRequest{
+					InstanceName: "instance",
+					Uris: []string{
+						"https://mirror.example.com/github/foo/bar/archive/v1.tar.gz",
+						"https://cdn.example.org/bar.tar.gz",
+					},
+				}, req)
+				return &remoteasset.FetchBlobResponse{
+					Status: status.New(codes.OK, "Success!").Proto(),
+					Uri:    "https://mirror.example.com/github/foo/bar/archive/v1.tar.gz",
+				}, nil
+			})
+
+		response, err := rewritingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris: []string{
+				"https://github.com/foo/bar/archive/v1.tar.gz",
+				"https://cdn.example.org/bar.tar.gz",
+				"https://example.com/bar.tar.gz",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://github.com/foo/bar/archive/v1.tar.gz", response.Uri)
+	})
+
+	t.Run("SchemelessReplacement", func(t *testing.T) {
+		baseFetcher.EXPECT().FetchBlob(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchBlobRequest) (*remoteasset.FetchBlobResponse, error) {
+				testutil.RequireEqualProto(t, &remoteasset.FetchBlobRequest{
+					InstanceName: "instance",
+					Uris: []string{
+						"http://mirror.example.com/net/baz.tar.gz",
+						"https://mirror.example.com/net/qux.tar.gz",
+					},
+				}, req)
+				return &remoteasset.FetchBlobResponse{
+					Status: status.New(codes.OK, "Success!").Proto(),
+					Uri:    "https://mirror.example.com/net/qux.tar.gz",
+				}, nil
+			})
+
+		response, err := rewritingFetcher.FetchBlob(ctx, &remoteasset.FetchBlobRequest{
+			InstanceName: "instance",
+			Uris: []string{
+				"http://downloads.example.net/baz.tar.gz",
+				"https://downloads.example.net/qux.tar.gz",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://downloads.example.net/qux.tar.gz", response.Uri)
+	})
+
+	t.Run("AuthHeaders", func(t *testing.T) {
+		// Headers should be applied to the rewritten URIs, while
+		// the response should report the original qualifiers.
+		qualifiers := []*remoteasset.Qualifier{
+			{Name: "bazel.auth_headers", Value: `{"https://github.com/foo/bar/archive/v1.tar.gz": {"Authorization": "Bearer letmein"}}`},
+			{Name: "checksum.sri", Value: "sha256-GF+NsyJx/iX1Yab8k4suJkMG7DBO2lGAB9F2SCY4GWk="},
+		}
+		baseFetcher.EXPECT().FetchDirectory(ctx, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, req *remoteasset.FetchDirectoryRequest) (*remoteasset.FetchDirectoryResponse, error) {
+				testutil.RequireEqualProto(t, &remoteasset.FetchDirectoryRequest{
+					InstanceName: "instance",
+					Uris:         []string{"https://mirror.example.com/github/foo/bar/archive/v1.tar.gz"},
+					Qualifiers: []*remoteasset.Qualifier{
+						{Name: "bazel.auth_headers", Value: `{"https://mirror.example.com/github/foo/bar/archive/v1.tar.gz":{"Authorization":"Bearer letmein"}}`},
+						{Name: "checksum.sri", Value: "sha256-GF+NsyJx/iX1Yab8k4suJkMG7DBO2lGAB9F2SCY4GWk="},
+					},
+				}, req)
+				return &remoteasset.FetchDirectoryResponse{
+					Status:     status.New(codes.OK, "Success!").Proto(),
+					Uri:        "https://mirror.example.com/github/foo/bar/archive/v1.tar.gz",
+					Qualifiers: req.Qualifiers,
+				}, nil
+			})
+
+		response, err := rewritingFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://github.com/foo/bar/archive/v1.tar.gz"},
+			Qualifiers:   qualifiers,
+		})
+		require.NoError(t, err)
+		require.Equal(t, "https://github.com/foo/bar/archive/v1.tar.gz", response.Uri)
+		require.Len(t, response.Qualifiers, 2)
+		testutil.RequireEqualProto(t, qualifiers[0], response.Qualifiers[0])
+	})
+
+	t.Run("AllBlocked", func(t *testing.T) {
+		_, err := rewritingFetcher.FetchDirectory(ctx, &remoteasset.FetchDirectoryRequest{
+			InstanceName: "instance",
+			Uris:         []string{"https://example.com/bar.tar.gz"},
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.PermissionDenied, "All URIs were blocked by the URL rewriting rules: Downloads must go through the mirror."), err)
+	})
+}
+
+func TestNewURLRewriterFromConfiguration(t *testing.T) {
+	t.Run("UnknownDirective", func(t *testing.T) {
+		_, err := fetch.NewURLRewriterFromConfiguration("redirect example.com")
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("InvalidPattern", func(t *testing.T) {
+		_, err := fetch.NewURLRewriterFromConfiguration("rewrite (example.com https://example.org")
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -86,6 +86,7 @@ type Asset struct {
 	Digest      *v2.Digest             `protobuf:"bytes,1,opt,name=digest,proto3" json:"digest,omitempty"`
 	ExpireAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expire_at,json=expireAt,proto3" json:"expire_at,omitempty"`
 	LastUpdated *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	OriginUri   string                 `protobuf:"bytes,4,opt,name=origin_uri,json=originUri,proto3" json:"origin_uri,omitempty"`
 }
 
 func (x *Asset) Reset() {
@@ -141,6 +142,60 @@ func (x *Asset) GetLastUpdated() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Asset) GetOriginUri() string {
+	if x != nil {
+		return x.OriginUri
+	}
+	return ""
+}
+
+type AssetReferenceList struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	References []*AssetReference `protobuf:"bytes,1,rep,name=references,proto3" json:"references,omitempty"`
+}
+
+func (x *AssetReferenceList) Reset() {
+	*x = AssetReferenceList{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_asset_asset_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AssetReferenceList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetReferenceList) ProtoMessage() {}
+
+func (x *AssetReferenceList) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_asset_asset_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetReferenceList.ProtoReflect.Descriptor instead.
+func (*AssetReferenceList) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_asset_asset_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AssetReferenceList) GetReferences() []*AssetReference {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
 var File_pkg_proto_asset_asset_proto protoreflect.FileDescriptor
 
 var file_pkg_proto_asset_asset_proto_rawDesc = []byte{
@@ -162,7 +217,7 @@ var file_pkg_proto_asset_asset_proto_rawDesc = []byte{
 	0x0b, 0x32, 0x26, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e,
 	0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x76, 0x31, 0x2e,
 	0x51, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x72, 0x52, 0x0a, 0x71, 0x75, 0x61, 0x6c, 0x69,
-	0x66, 0x69, 0x65, 0x72, 0x73, 0x22, 0xc0, 0x01, 0x0a, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12,
+	0x66, 0x69, 0x65, 0x72, 0x73, 0x22, 0xdf, 0x01, 0x0a, 0x05, 0x41, 0x73, 0x73, 0x65, 0x74, 0x12,
 	0x3f, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x27, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x2e, 0x62, 0x61, 0x7a, 0x65, 0x6c, 0x2e, 0x72, 0x65,
 	0x6d, 0x6f, 0x74, 0x65, 0x2e, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x76,
@@ -174,11 +229,18 @@ var file_pkg_proto_asset_asset_proto_rawDesc = []byte{
 	0x74, 0x5f, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
 	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
 	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0b, 0x6c, 0x61, 0x73,
-	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x42, 0x36, 0x5a, 0x34, 0x67, 0x69, 0x74, 0x68,
-	0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e,
-	0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x61, 0x73, 0x73, 0x65, 0x74,
-	0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x6f, 0x72, 0x69, 0x67,
+	0x69, 0x6e, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x72,
+	0x69, 0x67, 0x69, 0x6e, 0x55, 0x72, 0x69, 0x22, 0x55, 0x0a, 0x12, 0x41, 0x73, 0x73, 0x65, 0x74,
+	0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x4c, 0x69, 0x73, 0x74, 0x12, 0x3f, 0x0a,
+	0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x61, 0x73,
+	0x73, 0x65, 0x74, 0x2e, 0x41, 0x73, 0x73, 0x65, 0x74, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x52, 0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x42, 0x36,
+	0x5a, 0x34, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69,
+	0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x2f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -193,24 +255,26 @@ func file_pkg_proto_asset_asset_proto_rawDescGZIP() []byte {
 	return file_pkg_proto_asset_asset_proto_rawDescData
 }
 
-var file_pkg_proto_asset_asset_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pkg_proto_asset_asset_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
 var file_pkg_proto_asset_asset_proto_goTypes = []interface{}{
 	(*AssetReference)(nil),        // 0: buildbarn.asset.AssetReference
 	(*Asset)(nil),                 // 1: buildbarn.asset.Asset
-	(*v1.Qualifier)(nil),          // 2: build.bazel.remote.asset.v1.Qualifier
-	(*v2.Digest)(nil),             // 3: build.bazel.remote.execution.v2.Digest
-	(*timestamppb.Timestamp)(nil), // 4: google.protobuf.Timestamp
+	(*AssetReferenceList)(nil),    // 2: buildbarn.asset.AssetReferenceList
+	(*v1.Qualifier)(nil),          // 3: build.bazel.remote.asset.v1.Qualifier
+	(*v2.Digest)(nil),             // 4: build.bazel.remote.execution.v2.Digest
+	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
 }
 var file_pkg_proto_asset_asset_proto_depIdxs = []int32{
-	2, // 0: buildbarn.asset.AssetReference.qualifiers:type_name -> build.bazel.remote.asset.v1.Qualifier
-	3, // 1: buildbarn.asset.Asset.digest:type_name -> build.bazel.remote.execution.v2.Digest
-	4, // 2: buildbarn.asset.Asset.expire_at:type_name -> google.protobuf.Timestamp
-	4, // 3: buildbarn.asset.Asset.last_updated:type_name -> google.protobuf.Timestamp
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	3, // 0: buildbarn.asset.AssetReference.qualifiers:type_name -> build.bazel.remote.asset.v1.Qualifier
+	4, // 1: buildbarn.asset.Asset.digest:type_name -> build.bazel.remote.execution.v2.Digest
+	5, // 2: buildbarn.asset.Asset.expire_at:type_name -> google.protobuf.Timestamp
+	5, // 3: buildbarn.asset.Asset.last_updated:type_name -> google.protobuf.Timestamp
+	0, // 4: buildbarn.asset.AssetReferenceList.references:type_name -> buildbarn.asset.AssetReference
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_asset_asset_proto_init() }
@@ -243,6 +307,18 @@ func file_pkg_proto_asset_asset_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_proto_asset_asset_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AssetReferenceList); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -250,7 +326,7 @@ func file_pkg_proto_asset_asset_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_asset_asset_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   3,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
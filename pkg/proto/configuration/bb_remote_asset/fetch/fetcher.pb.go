@@ -12,6 +12,7 @@ import (
 	status "google.golang.org/genproto/googleapis/rpc/status"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -23,6 +24,55 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type FetcherConfiguration_HttpCredentialPrecedence int32
+
+const (
+	FetcherConfiguration_CLIENT_OVERRIDES_SERVER FetcherConfiguration_HttpCredentialPrecedence = 0
+	FetcherConfiguration_SERVER_OVERRIDES_CLIENT FetcherConfiguration_HttpCredentialPrecedence = 1
+	FetcherConfiguration_SERVER_EXCLUSIVE        FetcherConfiguration_HttpCredentialPrecedence = 2
+)
+
+// Enum value maps for FetcherConfiguration_HttpCredentialPrecedence.
+var (
+	FetcherConfiguration_HttpCredentialPrecedence_name = map[int32]string{
+		0: "CLIENT_OVERRIDES_SERVER",
+		1: "SERVER_OVERRIDES_CLIENT",
+		2: "SERVER_EXCLUSIVE",
+	}
+	FetcherConfiguration_HttpCredentialPrecedence_value = map[string]int32{
+		"CLIENT_OVERRIDES_SERVER": 0,
+		"SERVER_OVERRIDES_CLIENT": 1,
+		"SERVER_EXCLUSIVE":        2,
+	}
+)
+
+func (x FetcherConfiguration_HttpCredentialPrecedence) Enum() *FetcherConfiguration_HttpCredentialPrecedence {
+	p := new(FetcherConfiguration_HttpCredentialPrecedence)
+	*p = x
+	return p
+}
+
+func (x FetcherConfiguration_HttpCredentialPrecedence) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FetcherConfiguration_HttpCredentialPrecedence) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_enumTypes[0].Descriptor()
+}
+
+func (FetcherConfiguration_HttpCredentialPrecedence) Type() protoreflect.EnumType {
+	return &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_enumTypes[0]
+}
+
+func (x FetcherConfiguration_HttpCredentialPrecedence) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FetcherConfiguration_HttpCredentialPrecedence.Descriptor instead.
+func (FetcherConfiguration_HttpCredentialPrecedence) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 0}
+}
+
 type FetcherConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -33,7 +83,12 @@ type FetcherConfiguration struct {
 	//	*FetcherConfiguration_Http
 	//	*FetcherConfiguration_Error
 	//	*FetcherConfiguration_RemoteExecution
-	Backend isFetcherConfiguration_Backend `protobuf_oneof:"backend"`
+	Backend               isFetcherConfiguration_Backend                          `protobuf_oneof:"backend"`
+	DefaultTimeout        *durationpb.Duration                                    `protobuf:"bytes,5,opt,name=default_timeout,json=defaultTimeout,proto3" json:"default_timeout,omitempty"`
+	MaximumTimeout        *durationpb.Duration                                    `protobuf:"bytes,6,opt,name=maximum_timeout,json=maximumTimeout,proto3" json:"maximum_timeout,omitempty"`
+	UrlRewriteRules       string                                                  `protobuf:"bytes,7,opt,name=url_rewrite_rules,json=urlRewriteRules,proto3" json:"url_rewrite_rules,omitempty"`
+	NegativeCacheDuration *durationpb.Duration                                    `protobuf:"bytes,8,opt,name=negative_cache_duration,json=negativeCacheDuration,proto3" json:"negative_cache_duration,omitempty"`
+	CompletenessChecking  *FetcherConfiguration_CompletenessCheckingConfiguration `protobuf:"bytes,9,opt,name=completeness_checking,json=completenessChecking,proto3" json:"completeness_checking,omitempty"`
 }
 
 func (x *FetcherConfiguration) Reset() {
@@ -96,6 +151,41 @@ func (x *FetcherConfiguration) GetRemoteExecution() *FetcherConfiguration_Remote
 	return nil
 }
 
+func (x *FetcherConfiguration) GetDefaultTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.DefaultTimeout
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration) GetMaximumTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.MaximumTimeout
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration) GetUrlRewriteRules() string {
+	if x != nil {
+		return x.UrlRewriteRules
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration) GetNegativeCacheDuration() *durationpb.Duration {
+	if x != nil {
+		return x.NegativeCacheDuration
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration) GetCompletenessChecking() *FetcherConfiguration_CompletenessCheckingConfiguration {
+	if x != nil {
+		return x.CompletenessChecking
+	}
+	return nil
+}
+
 type isFetcherConfiguration_Backend interface {
 	isFetcherConfiguration_Backend()
 }
@@ -118,18 +208,82 @@ func (*FetcherConfiguration_Error) isFetcherConfiguration_Backend() {}
 
 func (*FetcherConfiguration_RemoteExecution) isFetcherConfiguration_Backend() {}
 
+type FetcherConfiguration_CompletenessCheckingConfiguration struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BatchSize       int32                `protobuf:"varint,1,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	RefreshInterval *durationpb.Duration `protobuf:"bytes,2,opt,name=refresh_interval,json=refreshInterval,proto3" json:"refresh_interval,omitempty"`
+}
+
+func (x *FetcherConfiguration_CompletenessCheckingConfiguration) Reset() {
+	*x = FetcherConfiguration_CompletenessCheckingConfiguration{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetcherConfiguration_CompletenessCheckingConfiguration) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetcherConfiguration_CompletenessCheckingConfiguration) ProtoMessage() {}
+
+func (x *FetcherConfiguration_CompletenessCheckingConfiguration) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetcherConfiguration_CompletenessCheckingConfiguration.ProtoReflect.Descriptor instead.
+func (*FetcherConfiguration_CompletenessCheckingConfiguration) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *FetcherConfiguration_CompletenessCheckingConfiguration) GetBatchSize() int32 {
+	if x != nil {
+		return x.BatchSize
+	}
+	return 0
+}
+
+func (x *FetcherConfiguration_CompletenessCheckingConfiguration) GetRefreshInterval() *durationpb.Duration {
+	if x != nil {
+		return x.RefreshInterval
+	}
+	return nil
+}
+
 type FetcherConfiguration_HttpFetcherConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Client *http.ClientConfiguration `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`
+	Client                        *http.ClientConfiguration                     `protobuf:"bytes,3,opt,name=client,proto3" json:"client,omitempty"`
+	MaximumInMemoryBodySizeBytes  int64                                         `protobuf:"varint,4,opt,name=maximum_in_memory_body_size_bytes,json=maximumInMemoryBodySizeBytes,proto3" json:"maximum_in_memory_body_size_bytes,omitempty"`
+	SpoolDirectoryPath            string                                        `protobuf:"bytes,5,opt,name=spool_directory_path,json=spoolDirectoryPath,proto3" json:"spool_directory_path,omitempty"`
+	RetryPolicy                   *FetcherConfiguration_HttpRetryPolicy         `protobuf:"bytes,6,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	Credentials                   []*FetcherConfiguration_HttpCredentials       `protobuf:"bytes,7,rep,name=credentials,proto3" json:"credentials,omitempty"`
+	NetrcPath                     string                                        `protobuf:"bytes,8,opt,name=netrc_path,json=netrcPath,proto3" json:"netrc_path,omitempty"`
+	CredentialPrecedence          FetcherConfiguration_HttpCredentialPrecedence `protobuf:"varint,9,opt,name=credential_precedence,json=credentialPrecedence,proto3,enum=buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration_HttpCredentialPrecedence" json:"credential_precedence,omitempty"`
+	CredentialHelperPath          string                                        `protobuf:"bytes,10,opt,name=credential_helper_path,json=credentialHelperPath,proto3" json:"credential_helper_path,omitempty"`
+	CredentialHelperCacheDuration *durationpb.Duration                          `protobuf:"bytes,11,opt,name=credential_helper_cache_duration,json=credentialHelperCacheDuration,proto3" json:"credential_helper_cache_duration,omitempty"`
+	MaximumResumeAttempts         uint32                                        `protobuf:"varint,12,opt,name=maximum_resume_attempts,json=maximumResumeAttempts,proto3" json:"maximum_resume_attempts,omitempty"`
 }
 
 func (x *FetcherConfiguration_HttpFetcherConfiguration) Reset() {
 	*x = FetcherConfiguration_HttpFetcherConfiguration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[1]
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -142,7 +296,7 @@ func (x *FetcherConfiguration_HttpFetcherConfiguration) String() string {
 func (*FetcherConfiguration_HttpFetcherConfiguration) ProtoMessage() {}
 
 func (x *FetcherConfiguration_HttpFetcherConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[1]
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -155,7 +309,7 @@ func (x *FetcherConfiguration_HttpFetcherConfiguration) ProtoReflect() protorefl
 
 // Deprecated: Use FetcherConfiguration_HttpFetcherConfiguration.ProtoReflect.Descriptor instead.
 func (*FetcherConfiguration_HttpFetcherConfiguration) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 0}
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 1}
 }
 
 func (x *FetcherConfiguration_HttpFetcherConfiguration) GetClient() *http.ClientConfiguration {
@@ -165,6 +319,399 @@ func (x *FetcherConfiguration_HttpFetcherConfiguration) GetClient() *http.Client
 	return nil
 }
 
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetMaximumInMemoryBodySizeBytes() int64 {
+	if x != nil {
+		return x.MaximumInMemoryBodySizeBytes
+	}
+	return 0
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetSpoolDirectoryPath() string {
+	if x != nil {
+		return x.SpoolDirectoryPath
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetRetryPolicy() *FetcherConfiguration_HttpRetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetCredentials() []*FetcherConfiguration_HttpCredentials {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetNetrcPath() string {
+	if x != nil {
+		return x.NetrcPath
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetCredentialPrecedence() FetcherConfiguration_HttpCredentialPrecedence {
+	if x != nil {
+		return x.CredentialPrecedence
+	}
+	return FetcherConfiguration_CLIENT_OVERRIDES_SERVER
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetCredentialHelperPath() string {
+	if x != nil {
+		return x.CredentialHelperPath
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetCredentialHelperCacheDuration() *durationpb.Duration {
+	if x != nil {
+		return x.CredentialHelperCacheDuration
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpFetcherConfiguration) GetMaximumResumeAttempts() uint32 {
+	if x != nil {
+		return x.MaximumResumeAttempts
+	}
+	return 0
+}
+
+type FetcherConfiguration_HttpCredentials struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Match:
+	//
+	//	*FetcherConfiguration_HttpCredentials_Host
+	//	*FetcherConfiguration_HttpCredentials_UrlPrefix
+	Match isFetcherConfiguration_HttpCredentials_Match `protobuf_oneof:"match"`
+	// Types that are assignable to Credentials:
+	//
+	//	*FetcherConfiguration_HttpCredentials_Headers
+	//	*FetcherConfiguration_HttpCredentials_BasicAuthentication
+	//	*FetcherConfiguration_HttpCredentials_BearerTokenPath
+	Credentials isFetcherConfiguration_HttpCredentials_Credentials `protobuf_oneof:"credentials"`
+}
+
+func (x *FetcherConfiguration_HttpCredentials) Reset() {
+	*x = FetcherConfiguration_HttpCredentials{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetcherConfiguration_HttpCredentials) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetcherConfiguration_HttpCredentials) ProtoMessage() {}
+
+func (x *FetcherConfiguration_HttpCredentials) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetcherConfiguration_HttpCredentials.ProtoReflect.Descriptor instead.
+func (*FetcherConfiguration_HttpCredentials) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (m *FetcherConfiguration_HttpCredentials) GetMatch() isFetcherConfiguration_HttpCredentials_Match {
+	if m != nil {
+		return m.Match
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpCredentials) GetHost() string {
+	if x, ok := x.GetMatch().(*FetcherConfiguration_HttpCredentials_Host); ok {
+		return x.Host
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration_HttpCredentials) GetUrlPrefix() string {
+	if x, ok := x.GetMatch().(*FetcherConfiguration_HttpCredentials_UrlPrefix); ok {
+		return x.UrlPrefix
+	}
+	return ""
+}
+
+func (m *FetcherConfiguration_HttpCredentials) GetCredentials() isFetcherConfiguration_HttpCredentials_Credentials {
+	if m != nil {
+		return m.Credentials
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpCredentials) GetHeaders() *FetcherConfiguration_HttpHeaders {
+	if x, ok := x.GetCredentials().(*FetcherConfiguration_HttpCredentials_Headers); ok {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpCredentials) GetBasicAuthentication() *FetcherConfiguration_HttpBasicAuthentication {
+	if x, ok := x.GetCredentials().(*FetcherConfiguration_HttpCredentials_BasicAuthentication); ok {
+		return x.BasicAuthentication
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpCredentials) GetBearerTokenPath() string {
+	if x, ok := x.GetCredentials().(*FetcherConfiguration_HttpCredentials_BearerTokenPath); ok {
+		return x.BearerTokenPath
+	}
+	return ""
+}
+
+type isFetcherConfiguration_HttpCredentials_Match interface {
+	isFetcherConfiguration_HttpCredentials_Match()
+}
+
+type FetcherConfiguration_HttpCredentials_Host struct {
+	Host string `protobuf:"bytes,1,opt,name=host,proto3,oneof"`
+}
+
+type FetcherConfiguration_HttpCredentials_UrlPrefix struct {
+	UrlPrefix string `protobuf:"bytes,2,opt,name=url_prefix,json=urlPrefix,proto3,oneof"`
+}
+
+func (*FetcherConfiguration_HttpCredentials_Host) isFetcherConfiguration_HttpCredentials_Match() {}
+
+func (*FetcherConfiguration_HttpCredentials_UrlPrefix) isFetcherConfiguration_HttpCredentials_Match() {
+}
+
+type isFetcherConfiguration_HttpCredentials_Credentials interface {
+	isFetcherConfiguration_HttpCredentials_Credentials()
+}
+
+type FetcherConfiguration_HttpCredentials_Headers struct {
+	Headers *FetcherConfiguration_HttpHeaders `protobuf:"bytes,3,opt,name=headers,proto3,oneof"`
+}
+
+type FetcherConfiguration_HttpCredentials_BasicAuthentication struct {
+	BasicAuthentication *FetcherConfiguration_HttpBasicAuthentication `protobuf:"bytes,4,opt,name=basic_authentication,json=basicAuthentication,proto3,oneof"`
+}
+
+type FetcherConfiguration_HttpCredentials_BearerTokenPath struct {
+	BearerTokenPath string `protobuf:"bytes,5,opt,name=bearer_token_path,json=bearerTokenPath,proto3,oneof"`
+}
+
+func (*FetcherConfiguration_HttpCredentials_Headers) isFetcherConfiguration_HttpCredentials_Credentials() {
+}
+
+func (*FetcherConfiguration_HttpCredentials_BasicAuthentication) isFetcherConfiguration_HttpCredentials_Credentials() {
+}
+
+func (*FetcherConfiguration_HttpCredentials_BearerTokenPath) isFetcherConfiguration_HttpCredentials_Credentials() {
+}
+
+type FetcherConfiguration_HttpHeaders struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Headers map[string]string `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *FetcherConfiguration_HttpHeaders) Reset() {
+	*x = FetcherConfiguration_HttpHeaders{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetcherConfiguration_HttpHeaders) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetcherConfiguration_HttpHeaders) ProtoMessage() {}
+
+func (x *FetcherConfiguration_HttpHeaders) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetcherConfiguration_HttpHeaders.ProtoReflect.Descriptor instead.
+func (*FetcherConfiguration_HttpHeaders) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 3}
+}
+
+func (x *FetcherConfiguration_HttpHeaders) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+type FetcherConfiguration_HttpBasicAuthentication struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+}
+
+func (x *FetcherConfiguration_HttpBasicAuthentication) Reset() {
+	*x = FetcherConfiguration_HttpBasicAuthentication{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetcherConfiguration_HttpBasicAuthentication) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetcherConfiguration_HttpBasicAuthentication) ProtoMessage() {}
+
+func (x *FetcherConfiguration_HttpBasicAuthentication) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetcherConfiguration_HttpBasicAuthentication.ProtoReflect.Descriptor instead.
+func (*FetcherConfiguration_HttpBasicAuthentication) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 4}
+}
+
+func (x *FetcherConfiguration_HttpBasicAuthentication) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *FetcherConfiguration_HttpBasicAuthentication) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type FetcherConfiguration_HttpRetryPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	MaximumAttempts      uint32               `protobuf:"varint,1,opt,name=maximum_attempts,json=maximumAttempts,proto3" json:"maximum_attempts,omitempty"`
+	InitialBackoff       *durationpb.Duration `protobuf:"bytes,2,opt,name=initial_backoff,json=initialBackoff,proto3" json:"initial_backoff,omitempty"`
+	BackoffMultiplier    float64              `protobuf:"fixed64,3,opt,name=backoff_multiplier,json=backoffMultiplier,proto3" json:"backoff_multiplier,omitempty"`
+	MaximumBackoff       *durationpb.Duration `protobuf:"bytes,4,opt,name=maximum_backoff,json=maximumBackoff,proto3" json:"maximum_backoff,omitempty"`
+	RetryableStatusCodes []uint32             `protobuf:"varint,5,rep,packed,name=retryable_status_codes,json=retryableStatusCodes,proto3" json:"retryable_status_codes,omitempty"`
+	RetryTransportErrors bool                 `protobuf:"varint,6,opt,name=retry_transport_errors,json=retryTransportErrors,proto3" json:"retry_transport_errors,omitempty"`
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) Reset() {
+	*x = FetcherConfiguration_HttpRetryPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FetcherConfiguration_HttpRetryPolicy) ProtoMessage() {}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FetcherConfiguration_HttpRetryPolicy.ProtoReflect.Descriptor instead.
+func (*FetcherConfiguration_HttpRetryPolicy) Descriptor() ([]byte, []int) {
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetMaximumAttempts() uint32 {
+	if x != nil {
+		return x.MaximumAttempts
+	}
+	return 0
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetInitialBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.InitialBackoff
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetBackoffMultiplier() float64 {
+	if x != nil {
+		return x.BackoffMultiplier
+	}
+	return 0
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetMaximumBackoff() *durationpb.Duration {
+	if x != nil {
+		return x.MaximumBackoff
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetRetryableStatusCodes() []uint32 {
+	if x != nil {
+		return x.RetryableStatusCodes
+	}
+	return nil
+}
+
+func (x *FetcherConfiguration_HttpRetryPolicy) GetRetryTransportErrors() bool {
+	if x != nil {
+		return x.RetryTransportErrors
+	}
+	return false
+}
+
 type FetcherConfiguration_RemoteExecutionFetcherConfiguration struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -176,7 +723,7 @@ type FetcherConfiguration_RemoteExecutionFetcherConfiguration struct {
 func (x *FetcherConfiguration_RemoteExecutionFetcherConfiguration) Reset() {
 	*x = FetcherConfiguration_RemoteExecutionFetcherConfiguration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[2]
+		mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -189,7 +736,7 @@ func (x *FetcherConfiguration_RemoteExecutionFetcherConfiguration) String() stri
 func (*FetcherConfiguration_RemoteExecutionFetcherConfiguration) ProtoMessage() {}
 
 func (x *FetcherConfiguration_RemoteExecutionFetcherConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[2]
+	mi := &file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -202,7 +749,7 @@ func (x *FetcherConfiguration_RemoteExecutionFetcherConfiguration) ProtoReflect(
 
 // Deprecated: Use FetcherConfiguration_RemoteExecutionFetcherConfiguration.ProtoReflect.Descriptor instead.
 func (*FetcherConfiguration_RemoteExecutionFetcherConfiguration) Descriptor() ([]byte, []int) {
-	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 1}
+	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZIP(), []int{0, 6}
 }
 
 func (x *FetcherConfiguration_RemoteExecutionFetcherConfiguration) GetExecutionClient() *grpc.ClientConfiguration {
@@ -221,14 +768,16 @@ var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDesc = [
 	0x66, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x2d, 0x62,
 	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
 	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
-	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x1a, 0x17, 0x67, 0x6f,
+	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x1a, 0x1e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x17, 0x67, 0x6f,
 	0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x72, 0x70, 0x63, 0x2f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x2e,
 	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x27, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x67,
 	0x72, 0x70, 0x63, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x27,
 	0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
 	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x68, 0x74, 0x74, 0x70, 0x2f, 0x68, 0x74, 0x74,
-	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xd7, 0x04, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63,
+	0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xec, 0x17, 0x0a, 0x14, 0x46, 0x65, 0x74, 0x63,
 	0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
 	0x12, 0x72, 0x0a, 0x04, 0x68, 0x74, 0x74, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x5c,
 	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
@@ -249,29 +798,183 @@ var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDesc = [
 	0x2e, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e,
 	0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
 	0x74, 0x69, 0x6f, 0x6e, 0x48, 0x00, 0x52, 0x0f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78,
-	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x71, 0x0a, 0x18, 0x48, 0x74, 0x74, 0x70, 0x46,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x42, 0x0a, 0x0f, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62,
+	0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x64, 0x65, 0x66,
+	0x61, 0x75, 0x6c, 0x74, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12, 0x42, 0x0a, 0x0f, 0x6d,
+	0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x0e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x12,
+	0x2a, 0x0a, 0x11, 0x75, 0x72, 0x6c, 0x5f, 0x72, 0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x5f, 0x72,
+	0x75, 0x6c, 0x65, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x75, 0x72, 0x6c, 0x52,
+	0x65, 0x77, 0x72, 0x69, 0x74, 0x65, 0x52, 0x75, 0x6c, 0x65, 0x73, 0x12, 0x51, 0x0a, 0x17, 0x6e,
+	0x65, 0x67, 0x61, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x64, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x15, 0x6e, 0x65, 0x67, 0x61, 0x74, 0x69, 0x76,
+	0x65, 0x43, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x9a,
+	0x01, 0x0a, 0x15, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x5f,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x65,
+	0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f,
+	0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x2e, 0x46,
+	0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73,
+	0x43, 0x68, 0x65, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x14, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x6e,
+	0x65, 0x73, 0x73, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x69, 0x6e, 0x67, 0x1a, 0x88, 0x01, 0x0a, 0x21,
+	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x65, 0x6e, 0x65, 0x73, 0x73, 0x43, 0x68, 0x65, 0x63,
+	0x6b, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x62, 0x61, 0x74, 0x63, 0x68, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x62, 0x61, 0x74, 0x63, 0x68, 0x53, 0x69, 0x7a, 0x65,
+	0x12, 0x44, 0x0a, 0x10, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x5f, 0x69, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f,
+	0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x72, 0x65, 0x66, 0x72, 0x65, 0x73, 0x68, 0x49, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x1a, 0xe0, 0x06, 0x0a, 0x18, 0x48, 0x74, 0x74, 0x70, 0x46,
 	0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
 	0x69, 0x6f, 0x6e, 0x12, 0x49, 0x0a, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e,
 	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x68, 0x74,
 	0x74, 0x70, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4a, 0x04,
-	0x08, 0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x1a, 0x83, 0x01, 0x0a, 0x23, 0x52,
-	0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x65,
-	0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x5c, 0x0a, 0x10, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x62,
-	0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
-	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6c, 0x69, 0x65,
-	0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x0f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x42, 0x09, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64, 0x4a, 0x04, 0x08, 0x01, 0x10,
-	0x02, 0x42, 0x54, 0x5a, 0x52, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62, 0x62, 0x2d, 0x72, 0x65, 0x6d,
-	0x6f, 0x74, 0x65, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x2f, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65,
-	0x74, 0x2f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x12, 0x47,
+	0x0a, 0x21, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x69, 0x6e, 0x5f, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x5f, 0x62, 0x6f, 0x64, 0x79, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79,
+	0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x1c, 0x6d, 0x61, 0x78, 0x69, 0x6d,
+	0x75, 0x6d, 0x49, 0x6e, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x42, 0x6f, 0x64, 0x79, 0x53, 0x69,
+	0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x70, 0x6f, 0x6f, 0x6c,
+	0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x73, 0x70, 0x6f, 0x6f, 0x6c, 0x44, 0x69, 0x72, 0x65,
+	0x63, 0x74, 0x6f, 0x72, 0x79, 0x50, 0x61, 0x74, 0x68, 0x12, 0x76, 0x0a, 0x0c, 0x72, 0x65, 0x74,
+	0x72, 0x79, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x53, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d,
+	0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x2e,
+	0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x74, 0x74, 0x70, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x0b, 0x72, 0x65, 0x74, 0x72, 0x79, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x12, 0x75, 0x0a, 0x0b, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73,
+	0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x53, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61,
+	0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74,
+	0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x74, 0x74, 0x70,
+	0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x52, 0x0b, 0x63, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x6e, 0x65, 0x74, 0x72,
+	0x63, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6e, 0x65,
+	0x74, 0x72, 0x63, 0x50, 0x61, 0x74, 0x68, 0x12, 0x91, 0x01, 0x0a, 0x15, 0x63, 0x72, 0x65, 0x64,
+	0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x70, 0x72, 0x65, 0x63, 0x65, 0x64, 0x65, 0x6e, 0x63,
+	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x5c, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62,
+	0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65,
+	0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x74, 0x74,
+	0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x63, 0x65,
+	0x64, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x14, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61,
+	0x6c, 0x50, 0x72, 0x65, 0x63, 0x65, 0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x34, 0x0a, 0x16, 0x63,
+	0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x68, 0x65, 0x6c, 0x70, 0x65, 0x72,
+	0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x63, 0x72, 0x65,
+	0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x48, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x50, 0x61, 0x74,
+	0x68, 0x12, 0x62, 0x0a, 0x20, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x5f,
+	0x68, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x5f, 0x63, 0x61, 0x63, 0x68, 0x65, 0x5f, 0x64, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x1d, 0x63, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69,
+	0x61, 0x6c, 0x48, 0x65, 0x6c, 0x70, 0x65, 0x72, 0x43, 0x61, 0x63, 0x68, 0x65, 0x44, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x36, 0x0a, 0x17, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x72, 0x65, 0x73, 0x75, 0x6d, 0x65, 0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x15, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x52,
+	0x65, 0x73, 0x75, 0x6d, 0x65, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x4a, 0x04, 0x08,
+	0x01, 0x10, 0x02, 0x4a, 0x04, 0x08, 0x02, 0x10, 0x03, 0x1a, 0x8e, 0x03, 0x0a, 0x0f, 0x48, 0x74,
+	0x74, 0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x12, 0x14, 0x0a,
+	0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x68,
+	0x6f, 0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0a, 0x75, 0x72, 0x6c, 0x5f, 0x70, 0x72, 0x65, 0x66, 0x69,
+	0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x75, 0x72, 0x6c, 0x50, 0x72,
+	0x65, 0x66, 0x69, 0x78, 0x12, 0x6b, 0x0a, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x4f, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e,
+	0x66, 0x65, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x74, 0x74, 0x70, 0x48,
+	0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x48, 0x01, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72,
+	0x73, 0x12, 0x90, 0x01, 0x0a, 0x14, 0x62, 0x61, 0x73, 0x69, 0x63, 0x5f, 0x61, 0x75, 0x74, 0x68,
+	0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x5b, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65,
+	0x6d, 0x6f, 0x74, 0x65, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68,
+	0x2e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x48, 0x74, 0x74, 0x70, 0x42, 0x61, 0x73, 0x69, 0x63, 0x41,
+	0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x48, 0x01, 0x52,
+	0x13, 0x62, 0x61, 0x73, 0x69, 0x63, 0x41, 0x75, 0x74, 0x68, 0x65, 0x6e, 0x74, 0x69, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x12, 0x2c, 0x0a, 0x11, 0x62, 0x65, 0x61, 0x72, 0x65, 0x72, 0x5f, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48,
+	0x01, 0x52, 0x0f, 0x62, 0x65, 0x61, 0x72, 0x65, 0x72, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x50, 0x61,
+	0x74, 0x68, 0x42, 0x07, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x42, 0x0d, 0x0a, 0x0b, 0x63,
+	0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x73, 0x1a, 0xc1, 0x01, 0x0a, 0x0b, 0x48,
+	0x74, 0x74, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x76, 0x0a, 0x07, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x5c, 0x2e, 0x62, 0x75,
+	0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x5f,
+	0x61, 0x73, 0x73, 0x65, 0x74, 0x2e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x65, 0x74, 0x63,
+	0x68, 0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x2e, 0x48, 0x74, 0x74, 0x70, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x2e, 0x48, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x07, 0x68, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x1a, 0x3a, 0x0a, 0x0c, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x51,
+	0x0a, 0x17, 0x48, 0x74, 0x74, 0x70, 0x42, 0x61, 0x73, 0x69, 0x63, 0x41, 0x75, 0x74, 0x68, 0x65,
+	0x6e, 0x74, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65,
+	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72,
+	0x64, 0x1a, 0xdf, 0x02, 0x0a, 0x0f, 0x48, 0x74, 0x74, 0x70, 0x52, 0x65, 0x74, 0x72, 0x79, 0x50,
+	0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x5f, 0x61, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x41, 0x74, 0x74, 0x65, 0x6d, 0x70, 0x74, 0x73,
+	0x12, 0x42, 0x0a, 0x0f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x5f, 0x62, 0x61, 0x63, 0x6b,
+	0x6f, 0x66, 0x66, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x6c, 0x42, 0x61, 0x63,
+	0x6b, 0x6f, 0x66, 0x66, 0x12, 0x2d, 0x0a, 0x12, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x5f,
+	0x6d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c, 0x69, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x11, 0x62, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x4d, 0x75, 0x6c, 0x74, 0x69, 0x70, 0x6c,
+	0x69, 0x65, 0x72, 0x12, 0x42, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d, 0x5f, 0x62,
+	0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44,
+	0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x6d, 0x61, 0x78, 0x69, 0x6d, 0x75, 0x6d,
+	0x42, 0x61, 0x63, 0x6b, 0x6f, 0x66, 0x66, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65, 0x74, 0x72, 0x79,
+	0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x5f, 0x63, 0x6f, 0x64, 0x65,
+	0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x14, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62,
+	0x6c, 0x65, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x43, 0x6f, 0x64, 0x65, 0x73, 0x12, 0x34, 0x0a,
+	0x16, 0x72, 0x65, 0x74, 0x72, 0x79, 0x5f, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74,
+	0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x72,
+	0x65, 0x74, 0x72, 0x79, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x70, 0x6f, 0x72, 0x74, 0x45, 0x72, 0x72,
+	0x6f, 0x72, 0x73, 0x1a, 0x83, 0x01, 0x0a, 0x23, 0x52, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x45, 0x78,
+	0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x46, 0x65, 0x74, 0x63, 0x68, 0x65, 0x72, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x5c, 0x0a, 0x10, 0x65,
+	0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x31, 0x2e, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72,
+	0x6e, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e,
+	0x67, 0x72, 0x70, 0x63, 0x2e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x22, 0x6a, 0x0a, 0x18, 0x48, 0x74, 0x74,
+	0x70, 0x43, 0x72, 0x65, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x61, 0x6c, 0x50, 0x72, 0x65, 0x63, 0x65,
+	0x64, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x17, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x5f,
+	0x4f, 0x56, 0x45, 0x52, 0x52, 0x49, 0x44, 0x45, 0x53, 0x5f, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52,
+	0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x4f, 0x56, 0x45,
+	0x52, 0x52, 0x49, 0x44, 0x45, 0x53, 0x5f, 0x43, 0x4c, 0x49, 0x45, 0x4e, 0x54, 0x10, 0x01, 0x12,
+	0x14, 0x0a, 0x10, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x5f, 0x45, 0x58, 0x43, 0x4c, 0x55, 0x53,
+	0x49, 0x56, 0x45, 0x10, 0x02, 0x42, 0x09, 0x0a, 0x07, 0x62, 0x61, 0x63, 0x6b, 0x65, 0x6e, 0x64,
+	0x4a, 0x04, 0x08, 0x01, 0x10, 0x02, 0x42, 0x54, 0x5a, 0x52, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62,
+	0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x62, 0x61, 0x72, 0x6e, 0x2f, 0x62,
+	0x62, 0x2d, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2d, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2f, 0x70,
+	0x6b, 0x67, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2f, 0x62, 0x62, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65,
+	0x5f, 0x61, 0x73, 0x73, 0x65, 0x74, 0x2f, 0x66, 0x65, 0x74, 0x63, 0x68, 0x62, 0x06, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -286,26 +989,49 @@ func file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescGZI
 	return file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDescData
 }
 
-var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
 var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_goTypes = []interface{}{
-	(*FetcherConfiguration)(nil),                                     // 0: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration
-	(*FetcherConfiguration_HttpFetcherConfiguration)(nil),            // 1: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration
-	(*FetcherConfiguration_RemoteExecutionFetcherConfiguration)(nil), // 2: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration
-	(*status.Status)(nil),                                            // 3: google.rpc.Status
-	(*http.ClientConfiguration)(nil),                                 // 4: buildbarn.configuration.http.ClientConfiguration
-	(*grpc.ClientConfiguration)(nil),                                 // 5: buildbarn.configuration.grpc.ClientConfiguration
+	(FetcherConfiguration_HttpCredentialPrecedence)(0),               // 0: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentialPrecedence
+	(*FetcherConfiguration)(nil),                                     // 1: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration
+	(*FetcherConfiguration_CompletenessCheckingConfiguration)(nil),   // 2: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.CompletenessCheckingConfiguration
+	(*FetcherConfiguration_HttpFetcherConfiguration)(nil),            // 3: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration
+	(*FetcherConfiguration_HttpCredentials)(nil),                     // 4: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentials
+	(*FetcherConfiguration_HttpHeaders)(nil),                         // 5: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpHeaders
+	(*FetcherConfiguration_HttpBasicAuthentication)(nil),             // 6: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpBasicAuthentication
+	(*FetcherConfiguration_HttpRetryPolicy)(nil),                     // 7: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpRetryPolicy
+	(*FetcherConfiguration_RemoteExecutionFetcherConfiguration)(nil), // 8: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration
+	nil,                              // 9: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpHeaders.HeadersEntry
+	(*status.Status)(nil),            // 10: google.rpc.Status
+	(*durationpb.Duration)(nil),      // 11: google.protobuf.Duration
+	(*http.ClientConfiguration)(nil), // 12: buildbarn.configuration.http.ClientConfiguration
+	(*grpc.ClientConfiguration)(nil), // 13: buildbarn.configuration.grpc.ClientConfiguration
 }
 var file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_depIdxs = []int32{
-	1, // 0: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.http:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration
-	3, // 1: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.error:type_name -> google.rpc.Status
-	2, // 2: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.remote_execution:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration
-	4, // 3: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.client:type_name -> buildbarn.configuration.http.ClientConfiguration
-	5, // 4: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration.execution_client:type_name -> buildbarn.configuration.grpc.ClientConfiguration
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	3,  // 0: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.http:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration
+	10, // 1: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.error:type_name -> google.rpc.Status
+	8,  // 2: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.remote_execution:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration
+	11, // 3: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.default_timeout:type_name -> google.protobuf.Duration
+	11, // 4: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.maximum_timeout:type_name -> google.protobuf.Duration
+	11, // 5: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.negative_cache_duration:type_name -> google.protobuf.Duration
+	2,  // 6: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.completeness_checking:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.CompletenessCheckingConfiguration
+	11, // 7: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.CompletenessCheckingConfiguration.refresh_interval:type_name -> google.protobuf.Duration
+	12, // 8: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.client:type_name -> buildbarn.configuration.http.ClientConfiguration
+	7,  // 9: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.retry_policy:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpRetryPolicy
+	4,  // 10: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.credentials:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentials
+	0,  // 11: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.credential_precedence:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentialPrecedence
+	11, // 12: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpFetcherConfiguration.credential_helper_cache_duration:type_name -> google.protobuf.Duration
+	5,  // 13: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentials.headers:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpHeaders
+	6,  // 14: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpCredentials.basic_authentication:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpBasicAuthentication
+	9,  // 15: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpHeaders.headers:type_name -> buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpHeaders.HeadersEntry
+	11, // 16: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpRetryPolicy.initial_backoff:type_name -> google.protobuf.Duration
+	11, // 17: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.HttpRetryPolicy.maximum_backoff:type_name -> google.protobuf.Duration
+	13, // 18: buildbarn.configuration.bb_remote_asset.fetch.FetcherConfiguration.RemoteExecutionFetcherConfiguration.execution_client:type_name -> buildbarn.configuration.grpc.ClientConfiguration
+	19, // [19:19] is the sub-list for method output_type
+	19, // [19:19] is the sub-list for method input_type
+	19, // [19:19] is the sub-list for extension type_name
+	19, // [19:19] is the sub-list for extension extendee
+	0,  // [0:19] is the sub-list for field type_name
 }
 
 func init() { file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_init() }
@@ -327,7 +1053,7 @@ func file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_init() {
 			}
 		}
 		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*FetcherConfiguration_HttpFetcherConfiguration); i {
+			switch v := v.(*FetcherConfiguration_CompletenessCheckingConfiguration); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -339,6 +1065,66 @@ func file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_init() {
 			}
 		}
 		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetcherConfiguration_HttpFetcherConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetcherConfiguration_HttpCredentials); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetcherConfiguration_HttpHeaders); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetcherConfiguration_HttpBasicAuthentication); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FetcherConfiguration_HttpRetryPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*FetcherConfiguration_RemoteExecutionFetcherConfiguration); i {
 			case 0:
 				return &v.state
@@ -356,18 +1142,26 @@ func file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_init() {
 		(*FetcherConfiguration_Error)(nil),
 		(*FetcherConfiguration_RemoteExecution)(nil),
 	}
+	file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*FetcherConfiguration_HttpCredentials_Host)(nil),
+		(*FetcherConfiguration_HttpCredentials_UrlPrefix)(nil),
+		(*FetcherConfiguration_HttpCredentials_Headers)(nil),
+		(*FetcherConfiguration_HttpCredentials_BasicAuthentication)(nil),
+		(*FetcherConfiguration_HttpCredentials_BearerTokenPath)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_rawDesc,
-			NumEnums:      0,
-			NumMessages:   3,
+			NumEnums:      1,
+			NumMessages:   9,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_goTypes,
 		DependencyIndexes: file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_depIdxs,
+		EnumInfos:         file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_enumTypes,
 		MessageInfos:      file_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto_msgTypes,
 	}.Build()
 	File_pkg_proto_configuration_bb_remote_asset_fetch_fetcher_proto = out.File
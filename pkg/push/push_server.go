@@ -14,14 +14,17 @@ import (
 type assetPushServer struct {
 	assetStore               storage.AssetStore
 	allowUpdatesForInstances map[digest.InstanceName]bool
+	freshnessPolicy          storage.FreshnessPolicy
 }
 
 // NewAssetPushServer creates a gRPC service for serving the contents
-// of a Remote Asset Push server.
-func NewAssetPushServer(AssetStore storage.AssetStore, allowUpdatesForInstances map[digest.InstanceName]bool) remoteasset.PushServer {
+// of a Remote Asset Push server. Expiration times provided by clients
+// are validated and limited by the FreshnessPolicy.
+func NewAssetPushServer(AssetStore storage.AssetStore, allowUpdatesForInstances map[digest.InstanceName]bool, freshnessPolicy storage.FreshnessPolicy) remoteasset.PushServer {
 	return &assetPushServer{
 		assetStore:               AssetStore,
 		allowUpdatesForInstances: allowUpdatesForInstances,
+		freshnessPolicy:          freshnessPolicy,
 	}
 }
 
@@ -39,8 +42,11 @@ func (s *assetPushServer) PushBlob(ctx context.Context, req *remoteasset.PushBlo
 		return nil, status.Errorf(codes.PermissionDenied, "This service does not accept Blobs for instance %#v", req.InstanceName)
 	}
 
+	assetData, err := s.freshnessPolicy.NewAsset(instanceName, req.BlobDigest, req.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
 	assetRef := storage.NewAssetReference(req.Uris, req.Qualifiers)
-	assetData := storage.NewAsset(req.BlobDigest, req.ExpireAt)
 	err = s.assetStore.Put(ctx, assetRef, assetData, instanceName)
 	if err != nil {
 		return nil, err
@@ -49,7 +55,6 @@ func (s *assetPushServer) PushBlob(ctx context.Context, req *remoteasset.PushBlo
 	if len(req.Uris) > 1 {
 		for _, uri := range req.Uris {
 			assetRef := storage.NewAssetReference([]string{uri}, req.Qualifiers)
-			assetData := storage.NewAsset(req.BlobDigest, req.ExpireAt)
 			err = s.assetStore.Put(ctx, assetRef, assetData, instanceName)
 			if err != nil {
 				return nil, err
@@ -73,8 +78,11 @@ func (s *assetPushServer) PushDirectory(ctx context.Context, req *remoteasset.Pu
 		return nil, status.Errorf(codes.PermissionDenied, "This service does not accept Directories for instance %#v", req.InstanceName)
 	}
 
+	assetData, err := s.freshnessPolicy.NewAsset(instanceName, req.RootDirectoryDigest, req.ExpireAt)
+	if err != nil {
+		return nil, err
+	}
 	assetRef := storage.NewAssetReference(req.Uris, req.Qualifiers)
-	assetData := storage.NewAsset(req.RootDirectoryDigest, req.ExpireAt)
 	err = s.assetStore.Put(ctx, assetRef, assetData, instanceName)
 	if err != nil {
 		return nil, err
@@ -83,7 +91,6 @@ func (s *assetPushServer) PushDirectory(ctx context.Context, req *remoteasset.Pu
 	if len(req.Uris) > 1 {
 		for _, uri := range req.Uris {
 			assetRef := storage.NewAssetReference([]string{uri}, req.Qualifiers)
-			assetData := storage.NewAsset(req.RootDirectoryDigest, req.ExpireAt)
 			err = s.assetStore.Put(ctx, assetRef, assetData, instanceName)
 			if err != nil {
 				return nil, err
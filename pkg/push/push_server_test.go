@@ -3,6 +3,7 @@ package push_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
@@ -11,12 +12,15 @@ import (
 	"github.com/buildbarn/bb-remote-asset/pkg/push"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/clock"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 func TestPushServerPushBlobSuccess(t *testing.T) {
@@ -55,7 +59,7 @@ func TestPushServerPushBlobSuccess(t *testing.T) {
 			return nil
 		})
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
-	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true})
+	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true}, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	response, err := pushServer.PushBlob(ctx, request)
 	require.NoError(t, err)
@@ -94,7 +98,7 @@ func TestPushServerPushDirectorySuccess(t *testing.T) {
 			return nil
 		})
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
-	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true})
+	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true}, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	response, err := pushServer.PushDirectory(ctx, request)
 	require.NoError(t, err)
@@ -116,7 +120,7 @@ func TestPushServerInvalidArgumentFailure(t *testing.T) {
 
 	backend := mock.NewMockBlobAccess(ctrl)
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
-	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true})
+	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true}, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	_, err = pushServer.PushBlob(ctx, blobRequest)
 	require.Equal(t, status.Error(codes.InvalidArgument, "PushBlob requires at least one URI"), err)
@@ -149,10 +153,54 @@ func TestPushServerBadInstanceName(t *testing.T) {
 
 	backend := mock.NewMockBlobAccess(ctrl)
 	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
-	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true})
+	pushServer := push.NewAssetPushServer(assetStore, map[digest.InstanceName]bool{instanceName: true}, storage.NewFreshnessPolicy(clock.SystemClock, storage.FreshnessParameters{}, nil))
 
 	_, err = pushServer.PushBlob(ctx, blobRequest)
 	require.Equal(t, status.Error(codes.PermissionDenied, "This service does not accept Blobs for instance \"bad\""), err)
 	_, err = pushServer.PushDirectory(ctx, directoryRequest)
 	require.Equal(t, status.Error(codes.PermissionDenied, "This service does not accept Directories for instance \"bad\""), err)
 }
+
+func TestPushServerExpiration(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("")
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	uri := "https://example.com/example.txt"
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	pushServer := push.NewAssetPushServer(
+		assetStore,
+		map[digest.InstanceName]bool{instanceName: true},
+		storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{MaximumTTL: time.Hour}, nil))
+
+	t.Run("InThePast", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		_, err := pushServer.PushBlob(ctx, &remoteasset.PushBlobRequest{
+			Uris:       []string{uri},
+			BlobDigest: blobDigest,
+			ExpireAt:   timestamppb.New(time.Unix(500, 0)),
+		})
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Expiration time 1970-01-01T00:08:20Z lies in the past"), err)
+	})
+
+	t.Run("BeyondMaximumTTL", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetStore.EXPECT().Put(ctx, storage.NewAssetReference([]string{uri}, nil), gomock.Any(), instanceName).DoAndReturn(
+			func(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+				testutil.RequireEqualProto(t, &asset.Asset{
+					Digest:      blobDigest,
+					ExpireAt:    timestamppb.New(time.Unix(4600, 0)),
+					LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+				}, data)
+				return nil
+			})
+		_, err := pushServer.PushBlob(ctx, &remoteasset.PushBlobRequest{
+			Uris:       []string{uri},
+			BlobDigest: blobDigest,
+			ExpireAt:   timestamppb.New(time.Unix(1000000, 0)),
+		})
+		require.NoError(t, err)
+	})
+}
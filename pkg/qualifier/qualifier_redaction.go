@@ -0,0 +1,30 @@
+package qualifier
+
+import (
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+)
+
+// SecretQualifiers contains the names of qualifiers whose values may
+// contain credentials. Their values must not be retained or displayed
+// beyond the request that provided them.
+var SecretQualifiers = NewSet([]string{
+	"auth.basic.password",
+	"bazel.auth_headers",
+})
+
+// RedactedValue replaces the values of secret qualifiers.
+const RedactedValue = "[redacted]"
+
+// Redact returns a copy of a list of qualifiers, in which the values of
+// qualifiers that may contain credentials are replaced by
+// RedactedValue.
+func Redact(qualifiers []*remoteasset.Qualifier) []*remoteasset.Qualifier {
+	redacted := make([]*remoteasset.Qualifier, 0, len(qualifiers))
+	for _, q := range qualifiers {
+		if SecretQualifiers.Contains(q.Name) {
+			q = &remoteasset.Qualifier{Name: q.Name, Value: RedactedValue}
+		}
+		redacted = append(redacted, q)
+	}
+	return redacted
+}
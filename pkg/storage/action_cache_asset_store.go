@@ -10,9 +10,11 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
@@ -86,20 +88,40 @@ func (rs *actionCacheAssetStore) actionResultToAsset(ctx context.Context, a *rem
 				digest = file.Digest
 			}
 		}
+		if digest.Hash == "" {
+			return nil, status.Error(codes.NotFound, "Asset has been deleted")
+		}
 	}
-	return &asset.Asset{
-		Digest:      digest,
-		ExpireAt:    getDefaultTimestamp(),
-		LastUpdated: a.ExecutionMetadata.QueuedTimestamp,
-	}, nil
+	// Restore the properties of the asset that were stored in the
+	// auxiliary metadata. Action results written by older versions
+	// lack these, in which case the asset does not expire.
+	assetData := &asset.Asset{}
+	for _, metadata := range a.GetExecutionMetadata().GetAuxiliaryMetadata() {
+		if metadata.MessageIs(assetData) {
+			if err := metadata.UnmarshalTo(assetData); err != nil {
+				return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to unmarshal asset metadata")
+			}
+			break
+		}
+	}
+	assetData.Digest = digest
+	if assetData.ExpireAt == nil {
+		assetData.ExpireAt = getDefaultTimestamp()
+	}
+	if assetData.LastUpdated == nil {
+		assetData.LastUpdated = a.GetExecutionMetadata().GetQueuedTimestamp()
+	}
+	return assetData, nil
 }
 
-func (rs *actionCacheAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+// assetReferenceToActionDigest computes the digest of the action under
+// which the ActionResult of an asset is stored in the Action Cache.
+func assetReferenceToActionDigest(ref *asset.AssetReference, instance digest.InstanceName) (digest.Digest, error) {
 	// Create asset reference using only the qualifiers of the request
 	qualifierReference := NewAssetReference(nil, ref.Qualifiers)
 	refDigest, err := ProtoToDigest(qualifierReference)
 	if err != nil {
-		return nil, err
+		return digest.BadDigest, err
 	}
 	// Construct a directory using the reference of only qualifiers
 	directory := &remoteexecution.Directory{
@@ -110,20 +132,20 @@ func (rs *actionCacheAssetStore) Get(ctx context.Context, ref *asset.AssetRefere
 	}
 	directoryDigest, err := ProtoToDigest(directory)
 	if err != nil {
-		return nil, err
+		return digest.BadDigest, err
 	}
 	var action *remoteexecution.Action
 	if commandGenerator, err := qualifier.QualifiersToCommand(ref.Qualifiers); err != nil || len(ref.Uris) > 1 {
 		// Create the action with the qualifier directory as the input root
 		action, _, err = assetReferenceToAction(ref, directoryDigest)
 		if err != nil {
-			return nil, err
+			return digest.BadDigest, err
 		}
 	} else {
 		command := commandGenerator(ref.Uris[0])
 		commandDigest, err := ProtoToDigest(command)
 		if err != nil {
-			return nil, err
+			return digest.BadDigest, err
 		}
 		action = &remoteexecution.Action{
 			CommandDigest:   commandDigest,
@@ -132,13 +154,17 @@ func (rs *actionCacheAssetStore) Get(ctx context.Context, ref *asset.AssetRefere
 	}
 	actionDigest, err := ProtoToDigest(action)
 	if err != nil {
-		return nil, err
+		return digest.BadDigest, err
 	}
 	digestFunction, err := instance.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(actionDigest.GetHash()))
 	if err != nil {
-		return nil, err
+		return digest.BadDigest, err
 	}
-	digest, err := digestFunction.NewDigestFromProto(actionDigest)
+	return digestFunction.NewDigestFromProto(actionDigest)
+}
+
+func (rs *actionCacheAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+	digest, err := assetReferenceToActionDigest(ref, instance)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +178,18 @@ func (rs *actionCacheAssetStore) Get(ctx context.Context, ref *asset.AssetRefere
 	return rs.actionResultToAsset(ctx, data.(*remoteexecution.ActionResult), instance)
 }
 
+// Delete an asset by overwriting its ActionResult with a tombstone. The
+// Action Cache does not provide a way to remove entries, so an
+// ActionResult without any outputs is stored instead, which Get()
+// reports as being absent.
+func (rs *actionCacheAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	digest, err := assetReferenceToActionDigest(ref, instance)
+	if err != nil {
+		return err
+	}
+	return rs.actionCache.Put(ctx, digest, buffer.NewProtoBufferFromProto(&remoteexecution.ActionResult{}, buffer.UserProvided))
+}
+
 func (rs *actionCacheAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
 	digestFunction, err := instance.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(data.GetDigest().GetHash()))
 	if err != nil {
@@ -255,9 +293,19 @@ func (rs *actionCacheAssetStore) Put(ctx context.Context, ref *asset.AssetRefere
 		return err
 	}
 
+	// Store all properties of the asset in the auxiliary metadata,
+	// so that they can be restored by Get(). The digest is omitted,
+	// as it is already stored as an output of the action result.
+	assetMetadata := proto.Clone(data).(*asset.Asset)
+	assetMetadata.Digest = nil
+	assetMetadataAny, err := anypb.New(assetMetadata)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to marshal asset metadata")
+	}
 	actionResult := &remoteexecution.ActionResult{
 		ExecutionMetadata: &remoteexecution.ExecutedActionMetadata{
-			QueuedTimestamp: data.LastUpdated,
+			QueuedTimestamp:   data.LastUpdated,
+			AuxiliaryMetadata: []*anypb.Any{assetMetadataAny},
 		},
 	}
 
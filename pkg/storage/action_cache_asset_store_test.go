@@ -8,6 +8,7 @@ import (
 	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
 	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
 	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
 	"github.com/buildbarn/bb-remote-asset/pkg/storage"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
@@ -268,3 +269,80 @@ func TestActionCacheAssetStoreGetDirectory(t *testing.T) {
 	_, err := assetStore.Get(ctx, assetRef, instanceName)
 	require.NoError(t, err)
 }
+
+func TestActionCacheAssetStoreRoundTrip(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("")
+
+	blobDigest := &remoteexecution.Digest{
+		Hash:      "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643",
+		SizeBytes: 111,
+	}
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"},
+		[]*remoteasset.Qualifier{{Name: "test", Value: "test"}})
+	assetData := &asset.Asset{
+		Digest:      blobDigest,
+		ExpireAt:    timestamppb.New(time.Unix(2000, 0)),
+		LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+	}
+
+	// The expiration time and the time of the last update should be
+	// preserved, instead of being replaced by defaults.
+	ac := mock.NewMockBlobAccess(ctrl)
+	cas := mock.NewMockBlobAccess(ctrl)
+	cas.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).Return(nil).Times(4)
+	cas.EXPECT().Get(ctx, gomock.Any()).Return(
+		buffer.NewValidatedBufferFromByteSlice([]byte("Hello")))
+	var storedActionResult buffer.Buffer
+	ac.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+			storedActionResult = b
+			return nil
+		})
+	assetStore := storage.NewActionCacheAssetStore(ac, cas, 16*1024*1024)
+	require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+
+	ac.EXPECT().Get(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest) buffer.Buffer {
+			return storedActionResult
+		})
+	storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(assetData, storedAsset))
+}
+
+func TestActionCacheAssetStoreDelete(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("")
+
+	uri := "https://example.com/example.txt"
+	assetRef := storage.NewAssetReference([]string{uri},
+		[]*remoteasset.Qualifier{})
+	actionDigest := digest.MustNewDigest(
+		"",
+		remoteexecution.DigestFunction_SHA256,
+		"1543af664d856ac553f43cca0f61b3b948bafd6802308d67f42bbc09cd042218",
+		140,
+	)
+
+	ac := mock.NewMockBlobAccess(ctrl)
+	cas := mock.NewMockBlobAccess(ctrl)
+	assetStore := storage.NewActionCacheAssetStore(ac, cas, 16*1024*1024)
+
+	// Deleting an asset should overwrite its action result with one
+	// that has no outputs.
+	var tombstone buffer.Buffer
+	ac.EXPECT().Put(ctx, actionDigest, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+			tombstone = b
+			return nil
+		})
+	require.NoError(t, assetStore.Delete(ctx, assetRef, instanceName))
+
+	// Subsequent attempts to obtain the asset should fail.
+	ac.EXPECT().Get(ctx, actionDigest).Return(tombstone)
+	_, err := assetStore.Get(ctx, assetRef, instanceName)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -12,4 +12,7 @@ import (
 type AssetStore interface {
 	Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error)
 	Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error
+	// Delete an asset, causing subsequent calls to Get() for the same
+	// reference to return NOT_FOUND until it is Put() again.
+	Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error
 }
@@ -11,16 +11,18 @@ import (
 // AuthorizingAssetStore wraps an asset store and validates requests against the authorizers
 type AuthorizingAssetStore struct {
 	AssetStore
-	fetchAuthorizer auth.Authorizer
-	pushAuthorizer  auth.Authorizer
+	fetchAuthorizer  auth.Authorizer
+	pushAuthorizer   auth.Authorizer
+	deleteAuthorizer auth.Authorizer
 }
 
 // NewAuthorizingAssetStore creates a new authorizing asset store
-func NewAuthorizingAssetStore(as AssetStore, fetchAuthorizer, pushAuthorizer auth.Authorizer) *AuthorizingAssetStore {
+func NewAuthorizingAssetStore(as AssetStore, fetchAuthorizer, pushAuthorizer, deleteAuthorizer auth.Authorizer) *AuthorizingAssetStore {
 	return &AuthorizingAssetStore{
 		as,
 		fetchAuthorizer,
 		pushAuthorizer,
+		deleteAuthorizer,
 	}
 }
 
@@ -39,3 +41,11 @@ func (aas *AuthorizingAssetStore) Put(ctx context.Context, ref *asset.AssetRefer
 	}
 	return aas.AssetStore.Put(ctx, ref, data, instanceName)
 }
+
+// Delete is a wrapper that validates credentials against DeleteAuthorizer
+func (aas *AuthorizingAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instanceName digest.InstanceName) error {
+	if err := auth.AuthorizeSingleInstanceName(ctx, aas.deleteAuthorizer, instanceName); err != nil {
+		return err
+	}
+	return aas.AssetStore.Delete(ctx, ref, instanceName)
+}
@@ -30,7 +30,8 @@ func TestAuthorizingBlobAccessGet(t *testing.T) {
 	baseStore := mock.NewMockAssetStore(ctrl)
 	fetchAuthorizer := mock.NewMockAuthorizer(ctrl)
 	pushAuthorizer := mock.NewMockAuthorizer(ctrl)
-	aas := storage.NewAuthorizingAssetStore(baseStore, fetchAuthorizer, pushAuthorizer)
+	deleteAuthorizer := mock.NewMockAuthorizer(ctrl)
+	aas := storage.NewAuthorizingAssetStore(baseStore, fetchAuthorizer, pushAuthorizer, deleteAuthorizer)
 
 	t.Run("Allowed", func(t *testing.T) {
 		fetchAuthorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{nil})
@@ -63,7 +64,8 @@ func TestAuthorizingBlobAccessPut(t *testing.T) {
 	baseStore := mock.NewMockAssetStore(ctrl)
 	fetchAuthorizer := mock.NewMockAuthorizer(ctrl)
 	pushAuthorizer := mock.NewMockAuthorizer(ctrl)
-	aas := storage.NewAuthorizingAssetStore(baseStore, fetchAuthorizer, pushAuthorizer)
+	deleteAuthorizer := mock.NewMockAuthorizer(ctrl)
+	aas := storage.NewAuthorizingAssetStore(baseStore, fetchAuthorizer, pushAuthorizer, deleteAuthorizer)
 
 	t.Run("Allowed", func(t *testing.T) {
 		pushAuthorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{nil})
@@ -80,3 +82,34 @@ func TestAuthorizingBlobAccessPut(t *testing.T) {
 		require.Equal(t, status.Error(codes.PermissionDenied, "None shall pass"), err)
 	})
 }
+
+func TestAuthorizingBlobAccessDelete(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := bb_digest.MustNewInstanceName("rohan")
+	instanceSlice := []bb_digest.InstanceName{instanceName}
+
+	uri := "https://raapi.test/blob"
+	assetRef := storage.NewAssetReference([]string{uri}, []*remoteasset.Qualifier{})
+
+	baseStore := mock.NewMockAssetStore(ctrl)
+	fetchAuthorizer := mock.NewMockAuthorizer(ctrl)
+	pushAuthorizer := mock.NewMockAuthorizer(ctrl)
+	deleteAuthorizer := mock.NewMockAuthorizer(ctrl)
+	aas := storage.NewAuthorizingAssetStore(baseStore, fetchAuthorizer, pushAuthorizer, deleteAuthorizer)
+
+	t.Run("Allowed", func(t *testing.T) {
+		deleteAuthorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{nil})
+		baseStore.EXPECT().Delete(ctx, assetRef, instanceName).Return(nil)
+
+		err := aas.Delete(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+	})
+
+	t.Run("Rejected", func(t *testing.T) {
+		deleteAuthorizer.EXPECT().Authorize(ctx, instanceSlice).Return([]error{status.Error(codes.PermissionDenied, "None shall pass")})
+
+		err := aas.Delete(ctx, assetRef, instanceName)
+		require.Equal(t, status.Error(codes.PermissionDenied, "None shall pass"), err)
+	})
+}
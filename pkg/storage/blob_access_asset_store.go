@@ -7,6 +7,9 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // blobAccessAssetStore is an AssetStore backed by a blobAccess.
@@ -36,7 +39,11 @@ func (rs *blobAccessAssetStore) Get(ctx context.Context, ref *asset.AssetReferen
 	if err != nil {
 		return nil, err
 	}
-	return data.(*asset.Asset), nil
+	assetData := data.(*asset.Asset)
+	if assetData.Digest == nil {
+		return nil, status.Error(codes.NotFound, "Asset has been deleted")
+	}
+	return assetData, nil
 }
 
 // Put a digest into the store referenced by a given reference
@@ -47,3 +54,14 @@ func (rs *blobAccessAssetStore) Put(ctx context.Context, ref *asset.AssetReferen
 	}
 	return rs.blobAccess.Put(ctx, refDigest, buffer.NewProtoBufferFromProto(data, buffer.UserProvided))
 }
+
+// Delete an asset by overwriting it with a tombstone. BlobAccess does
+// not provide a way to remove objects, so an asset without a digest is
+// stored instead, which Get() reports as being absent.
+func (rs *blobAccessAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	refDigest, err := AssetReferenceToDigest(ref, instance)
+	if err != nil {
+		return err
+	}
+	return rs.blobAccess.Put(ctx, refDigest, buffer.NewProtoBufferFromProto(&asset.Asset{}, buffer.UserProvided))
+}
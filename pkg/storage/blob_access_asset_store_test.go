@@ -13,6 +13,8 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/digest"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
@@ -66,3 +68,33 @@ func TestBlobAccessAssetStoreGet(t *testing.T) {
 	_, err = assetStore.Get(ctx, assetRef, instanceName)
 	require.NoError(t, err)
 }
+
+func TestBlobAccessAssetStoreDelete(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName, err := digest.NewInstanceName("foo")
+	require.NoError(t, err)
+
+	uri := "https://example.com/example.txt"
+	assetRef := storage.NewAssetReference([]string{uri}, []*remoteasset.Qualifier{})
+	refDigest, err := storage.AssetReferenceToDigest(assetRef, instanceName)
+	require.NoError(t, err)
+
+	backend := mock.NewMockBlobAccess(ctrl)
+	assetStore := storage.NewBlobAccessAssetStore(backend, 16*1024*1024)
+
+	// Deleting an asset should overwrite it with an asset that does
+	// not have a digest.
+	var tombstone buffer.Buffer
+	backend.EXPECT().Put(ctx, refDigest, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+			tombstone = b
+			return nil
+		})
+	require.NoError(t, assetStore.Delete(ctx, assetRef, instanceName))
+
+	// Subsequent attempts to obtain the asset should fail.
+	backend.EXPECT().Get(ctx, refDigest).Return(tombstone)
+	_, err = assetStore.Get(ctx, assetRef, instanceName)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
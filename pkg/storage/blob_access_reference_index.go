@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"sync"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/blobstore"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/protobuf/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type blobAccessReferenceIndex struct {
+	blobAccess              blobstore.BlobAccess
+	maximumMessageSizeBytes int
+	maximumReferences       int
+
+	// Updates are read-modify-write cycles. Serialize updates of
+	// the same entry within this process, so that concurrent
+	// updates don't discard each other's references.
+	locks [256]sync.Mutex
+}
+
+// NewBlobAccessReferenceIndex creates a ReferenceIndex that stores the
+// references of all assets that refer to the same digest as a single
+// AssetReferenceList in a BlobAccess. To keep entries bounded in size,
+// only the most recently added maximumReferences references are
+// retained.
+//
+// The BlobAccess must not be shared with an asset store, as the keys
+// of both may overlap.
+func NewBlobAccessReferenceIndex(blobAccess blobstore.BlobAccess, maximumMessageSizeBytes, maximumReferences int) ReferenceIndex {
+	return &blobAccessReferenceIndex{
+		blobAccess:              blobAccess,
+		maximumMessageSizeBytes: maximumMessageSizeBytes,
+		maximumReferences:       maximumReferences,
+	}
+}
+
+// getReferenceIndexKey returns the key under which the references to a digest are
+// stored.
+func getReferenceIndexKey(blobDigest *remoteexecution.Digest, instance digest.InstanceName) (digest.Digest, error) {
+	wireFormat, err := proto.Marshal(&remoteexecution.Digest{
+		Hash:      blobDigest.GetHash(),
+		SizeBytes: blobDigest.GetSizeBytes(),
+	})
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	digestFunction, err := instance.GetDigestFunction(remoteexecution.DigestFunction_SHA256, 0)
+	if err != nil {
+		return digest.BadDigest, err
+	}
+	generator := digestFunction.NewGenerator(int64(len(wireFormat)))
+	if _, err := generator.Write(wireFormat); err != nil {
+		return digest.BadDigest, err
+	}
+	return generator.Sum(), nil
+}
+
+func (ri *blobAccessReferenceIndex) get(ctx context.Context, key digest.Digest) ([]*asset.AssetReference, error) {
+	m, err := ri.blobAccess.Get(ctx, key).ToProto(&asset.AssetReferenceList{}, ri.maximumMessageSizeBytes)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return m.(*asset.AssetReferenceList).References, nil
+}
+
+func (ri *blobAccessReferenceIndex) Add(ctx context.Context, blobDigest *remoteexecution.Digest, ref *asset.AssetReference, instance digest.InstanceName) error {
+	key, err := getReferenceIndexKey(blobDigest, instance)
+	if err != nil {
+		return err
+	}
+	lock := &ri.locks[key.GetHashBytes()[0]]
+	lock.Lock()
+	defer lock.Unlock()
+
+	existingRefs, err := ri.get(ctx, key)
+	if err != nil {
+		return util.StatusWrap(err, "Failed to read reference index entry")
+	}
+
+	// Move the reference to the end of the list if it's already
+	// present, so that the least recently added references are the
+	// ones that get discarded.
+	refs := make([]*asset.AssetReference, 0, len(existingRefs)+1)
+	for _, existingRef := range existingRefs {
+		if !proto.Equal(existingRef, ref) {
+			refs = append(refs, existingRef)
+		}
+	}
+	refs = append(refs, ref)
+	if len(refs) > ri.maximumReferences {
+		refs = refs[len(refs)-ri.maximumReferences:]
+	}
+
+	if err := ri.blobAccess.Put(ctx, key, buffer.NewProtoBufferFromProto(&asset.AssetReferenceList{References: refs}, buffer.UserProvided)); err != nil {
+		return util.StatusWrap(err, "Failed to write reference index entry")
+	}
+	return nil
+}
+
+func (ri *blobAccessReferenceIndex) Get(ctx context.Context, blobDigest *remoteexecution.Digest, instance digest.InstanceName) ([]*asset.AssetReference, error) {
+	key, err := getReferenceIndexKey(blobDigest, instance)
+	if err != nil {
+		return nil, err
+	}
+	return ri.get(ctx, key)
+}
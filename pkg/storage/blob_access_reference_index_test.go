@@ -0,0 +1,132 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBlobAccessReferenceIndexAdd(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName, err := digest.NewInstanceName("foo")
+	require.NoError(t, err)
+
+	blobDigest := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	ref1 := storage.NewAssetReference([]string{"https://example.com/1.txt"}, []*remoteasset.Qualifier{})
+	ref2 := storage.NewAssetReference([]string{"https://example.com/2.txt"}, []*remoteasset.Qualifier{})
+	ref3 := storage.NewAssetReference([]string{"https://example.com/3.txt"}, []*remoteasset.Qualifier{})
+
+	blobAccess := mock.NewMockBlobAccess(ctrl)
+	referenceIndex := storage.NewBlobAccessReferenceIndex(blobAccess, 16*1024*1024, 2)
+
+	t.Run("Empty", func(t *testing.T) {
+		blobAccess.EXPECT().Get(ctx, gomock.Any()).Return(buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found")))
+		blobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&asset.AssetReferenceList{}, 1000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &asset.AssetReferenceList{
+					References: []*asset.AssetReference{ref1},
+				}, m)
+				return nil
+			})
+
+		require.NoError(t, referenceIndex.Add(ctx, blobDigest, ref1, instanceName))
+	})
+
+	t.Run("Duplicate", func(t *testing.T) {
+		// Adding an existing reference should move it to the end
+		// of the list.
+		blobAccess.EXPECT().Get(ctx, gomock.Any()).Return(buffer.NewProtoBufferFromProto(&asset.AssetReferenceList{
+			References: []*asset.AssetReference{ref1, ref2},
+		}, buffer.UserProvided))
+		blobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&asset.AssetReferenceList{}, 1000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &asset.AssetReferenceList{
+					References: []*asset.AssetReference{ref2, ref1},
+				}, m)
+				return nil
+			})
+
+		require.NoError(t, referenceIndex.Add(ctx, blobDigest, ref1, instanceName))
+	})
+
+	t.Run("Truncate", func(t *testing.T) {
+		blobAccess.EXPECT().Get(ctx, gomock.Any()).Return(buffer.NewProtoBufferFromProto(&asset.AssetReferenceList{
+			References: []*asset.AssetReference{ref1, ref2},
+		}, buffer.UserProvided))
+		blobAccess.EXPECT().Put(ctx, gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, digest digest.Digest, b buffer.Buffer) error {
+				m, err := b.ToProto(&asset.AssetReferenceList{}, 1000)
+				require.NoError(t, err)
+				testutil.RequireEqualProto(t, &asset.AssetReferenceList{
+					References: []*asset.AssetReference{ref2, ref3},
+				}, m)
+				return nil
+			})
+
+		require.NoError(t, referenceIndex.Add(ctx, blobDigest, ref3, instanceName))
+	})
+
+	t.Run("ReadFailure", func(t *testing.T) {
+		blobAccess.EXPECT().Get(ctx, gomock.Any()).Return(buffer.NewBufferFromError(status.Error(codes.Unavailable, "Server offline")))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Failed to read reference index entry: Server offline"),
+			referenceIndex.Add(ctx, blobDigest, ref1, instanceName))
+	})
+}
+
+func TestBlobAccessReferenceIndexGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName, err := digest.NewInstanceName("foo")
+	require.NoError(t, err)
+
+	blobDigest := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	otherBlobDigest := &remoteexecution.Digest{Hash: "aec070645fe53ee3b3763059376134f058cc337247c978add178b6ccdfb0019f", SizeBytes: 222}
+	ref := storage.NewAssetReference([]string{"https://example.com/1.txt"}, []*remoteasset.Qualifier{})
+
+	blobAccess := mock.NewMockBlobAccess(ctrl)
+	referenceIndex := storage.NewBlobAccessReferenceIndex(blobAccess, 16*1024*1024, 10)
+
+	// Entries for different digests should be stored under
+	// different keys.
+	var key digest.Digest
+	blobAccess.EXPECT().Get(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest) buffer.Buffer {
+			key = digest
+			return buffer.NewProtoBufferFromProto(&asset.AssetReferenceList{
+				References: []*asset.AssetReference{ref},
+			}, buffer.UserProvided)
+		})
+	refs, err := referenceIndex.Get(ctx, blobDigest, instanceName)
+	require.NoError(t, err)
+	require.Len(t, refs, 1)
+	testutil.RequireEqualProto(t, ref, refs[0])
+
+	blobAccess.EXPECT().Get(ctx, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, digest digest.Digest) buffer.Buffer {
+			require.NotEqual(t, key, digest)
+			return buffer.NewBufferFromError(status.Error(codes.NotFound, "Object not found"))
+		})
+	refs, err = referenceIndex.Get(ctx, otherBlobDigest, instanceName)
+	require.NoError(t, err)
+	require.Empty(t, refs)
+}
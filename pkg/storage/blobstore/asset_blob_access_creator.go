@@ -20,6 +20,8 @@ type assetBlobAccessCreator struct {
 
 	grpcClientFactory       grpc.ClientFactory
 	maximumMessageSizeBytes int
+	readBufferFactory       blobstore.ReadBufferFactory
+	storageTypeName         string
 }
 
 // NewAssetBlobAccessCreator creates a new BlobAccessCreator suitable for creating BlobAccesses
@@ -28,6 +30,20 @@ func NewAssetBlobAccessCreator(grpcClientFactory grpc.ClientFactory, maximumMess
 	return &assetBlobAccessCreator{
 		grpcClientFactory:       grpcClientFactory,
 		maximumMessageSizeBytes: maximumMessageSizeBytes,
+		readBufferFactory:       AssetReadBufferFactory,
+		storageTypeName:         "asset",
+	}
+}
+
+// NewReferenceIndexBlobAccessCreator creates a new BlobAccessCreator
+// suitable for creating BlobAccesses used for storage of the reference
+// index, which maps digests to the Assets that refer to them.
+func NewReferenceIndexBlobAccessCreator(grpcClientFactory grpc.ClientFactory, maximumMessageSizeBytes int) configuration.BlobAccessCreator {
+	return &assetBlobAccessCreator{
+		grpcClientFactory:       grpcClientFactory,
+		maximumMessageSizeBytes: maximumMessageSizeBytes,
+		readBufferFactory:       ReferenceIndexReadBufferFactory,
+		storageTypeName:         "reference_index",
 	}
 }
 
@@ -36,11 +52,11 @@ func (bac *assetBlobAccessCreator) GetBaseDigestKeyFormat() digest.KeyFormat {
 }
 
 func (bac *assetBlobAccessCreator) GetReadBufferFactory() blobstore.ReadBufferFactory {
-	return AssetReadBufferFactory
+	return bac.readBufferFactory
 }
 
 func (bac *assetBlobAccessCreator) GetStorageTypeName() string {
-	return "asset"
+	return bac.storageTypeName
 }
 
 func (bac *assetBlobAccessCreator) NewCustomBlobAccess(config *pb.BlobAccessConfiguration, creator configuration.NestedBlobAccessCreator) (configuration.BlobAccessInfo, string, error) {
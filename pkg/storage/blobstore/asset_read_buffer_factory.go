@@ -8,19 +8,24 @@ import (
 	"github.com/buildbarn/bb-storage/pkg/blobstore"
 	"github.com/buildbarn/bb-storage/pkg/blobstore/buffer"
 	"github.com/buildbarn/bb-storage/pkg/digest"
+	"google.golang.org/protobuf/proto"
 )
 
-type assetReadBufferFactory struct{}
+// protoReadBufferFactory creates buffers for objects that contain
+// Protobuf messages of a given type.
+type protoReadBufferFactory struct {
+	newMessage func() proto.Message
+}
 
-func (f assetReadBufferFactory) NewBufferFromByteSlice(digest digest.Digest, data []byte, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
-	return buffer.NewProtoBufferFromByteSlice(&asset.Asset{}, data, buffer.BackendProvided(dataIntegrityCallback))
+func (f protoReadBufferFactory) NewBufferFromByteSlice(digest digest.Digest, data []byte, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
+	return buffer.NewProtoBufferFromByteSlice(f.newMessage(), data, buffer.BackendProvided(dataIntegrityCallback))
 }
 
-func (f assetReadBufferFactory) NewBufferFromReader(digest digest.Digest, r io.ReadCloser, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
-	return buffer.NewProtoBufferFromReader(&asset.Asset{}, r, buffer.BackendProvided(dataIntegrityCallback))
+func (f protoReadBufferFactory) NewBufferFromReader(digest digest.Digest, r io.ReadCloser, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
+	return buffer.NewProtoBufferFromReader(f.newMessage(), r, buffer.BackendProvided(dataIntegrityCallback))
 }
 
-func (f assetReadBufferFactory) NewBufferFromReaderAt(digest digest.Digest, r buffer.ReadAtCloser, sizeBytes int64, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
+func (f protoReadBufferFactory) NewBufferFromReaderAt(digest digest.Digest, r buffer.ReadAtCloser, sizeBytes int64, dataIntegrityCallback buffer.DataIntegrityCallback) buffer.Buffer {
 	return f.NewBufferFromReader(digest, newReaderFromReaderAt(r), dataIntegrityCallback)
 }
 
@@ -36,4 +41,12 @@ func newReaderFromReaderAt(r buffer.ReadAtCloser) io.ReadCloser {
 
 // AssetReadBufferFactory is capable of buffers for objects stored in
 // the Asset Store.
-var AssetReadBufferFactory blobstore.ReadBufferFactory = assetReadBufferFactory{}
+var AssetReadBufferFactory blobstore.ReadBufferFactory = protoReadBufferFactory{
+	newMessage: func() proto.Message { return &asset.Asset{} },
+}
+
+// ReferenceIndexReadBufferFactory is capable of buffers for objects
+// stored in the reference index.
+var ReferenceIndexReadBufferFactory blobstore.ReadBufferFactory = protoReadBufferFactory{
+	newMessage: func() proto.Message { return &asset.AssetReferenceList{} },
+}
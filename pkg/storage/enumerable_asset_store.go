@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"time"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// EnumeratedAsset is an asset returned by EnumerableAssetStore.List(),
+// together with the reference under which it is stored.
+type EnumeratedAsset struct {
+	Reference *asset.AssetReference
+	Asset     *asset.Asset
+}
+
+// AssetStoreStatistics summarizes the assets stored for an instance
+// name.
+type AssetStoreStatistics struct {
+	Assets            int64
+	ExpiredAssets     int64
+	DistinctDigests   int64
+	OldestLastUpdated time.Time
+	NewestLastUpdated time.Time
+}
+
+// EnumerableAssetStore is an AssetStore whose contents can be
+// inspected and expired in bulk, as opposed to only being accessible
+// by reference.
+type EnumerableAssetStore interface {
+	AssetStore
+
+	// List the assets stored for an instance name, having at least
+	// one URI that starts with a given prefix. Results are returned
+	// in pages. The returned page token may be provided to obtain
+	// the next page, and is empty when no more results are present.
+	List(ctx context.Context, instance digest.InstanceName, uriPrefix, pageToken string, pageSize int) ([]EnumeratedAsset, string, error)
+
+	// Delete all assets, regardless of their instance name, whose
+	// expiration time lies before a given time. The number of
+	// deleted assets is returned.
+	DeleteExpired(ctx context.Context, expiredBefore time.Time) (int64, error)
+
+	// GetStatistics returns statistics on the assets stored for an
+	// instance name.
+	GetStatistics(ctx context.Context, instance digest.InstanceName) (*AssetStoreStatistics, error)
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/program"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	expiredAssetSweeperPrometheusMetrics sync.Once
+
+	expiredAssetSweeperSweepDurationSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "buildbarn",
+			Subsystem: "remote_asset",
+			Name:      "expired_asset_sweeper_sweep_duration_seconds",
+			Help:      "Amount of time spent per sweep of expired assets, in seconds.",
+			Buckets:   util.DecimalExponentialBuckets(-3, 6, 2),
+		},
+		[]string{"result"})
+	expiredAssetSweeperDeletedAssets = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "buildbarn",
+			Subsystem: "remote_asset",
+			Name:      "expired_asset_sweeper_deleted_assets_total",
+			Help:      "Number of expired assets deleted from the asset cache.",
+		})
+)
+
+// ExpiredAssetSweeper periodically deletes assets whose expiration
+// time has passed from an EnumerableAssetStore. Without it, such
+// assets are merely ignored when read, while continuing to take up
+// space.
+type ExpiredAssetSweeper struct {
+	assetStore      EnumerableAssetStore
+	freshnessPolicy FreshnessPolicy
+	clock           clock.Clock
+	interval        time.Duration
+	gracePeriod     time.Duration
+
+	sweepDurationSecondsSuccess prometheus.Observer
+	sweepDurationSecondsFailure prometheus.Observer
+}
+
+// NewExpiredAssetSweeper creates an ExpiredAssetSweeper that deletes
+// assets once every interval. Assets are deleted once they may no
+// longer be returned while stale according to the FreshnessPolicy,
+// and an additional grace period has passed.
+func NewExpiredAssetSweeper(assetStore EnumerableAssetStore, freshnessPolicy FreshnessPolicy, clock clock.Clock, interval, gracePeriod time.Duration) *ExpiredAssetSweeper {
+	expiredAssetSweeperPrometheusMetrics.Do(func() {
+		prometheus.MustRegister(expiredAssetSweeperSweepDurationSeconds)
+		prometheus.MustRegister(expiredAssetSweeperDeletedAssets)
+	})
+
+	return &ExpiredAssetSweeper{
+		assetStore:      assetStore,
+		freshnessPolicy: freshnessPolicy,
+		clock:           clock,
+		interval:        interval,
+		gracePeriod:     gracePeriod,
+
+		sweepDurationSecondsSuccess: expiredAssetSweeperSweepDurationSeconds.WithLabelValues("Success"),
+		sweepDurationSecondsFailure: expiredAssetSweeperSweepDurationSeconds.WithLabelValues("Failure"),
+	}
+}
+
+func (s *ExpiredAssetSweeper) sweep(ctx context.Context) {
+	timeStart := s.clock.Now()
+	retention := s.freshnessPolicy.GetMaximumStaleWhileRevalidate() + s.gracePeriod
+	deleted, err := s.assetStore.DeleteExpired(ctx, timeStart.Add(-retention))
+	duration := s.clock.Now().Sub(timeStart).Seconds()
+	if err != nil {
+		s.sweepDurationSecondsFailure.Observe(duration)
+		log.Printf("Failed to delete expired assets: %v", err)
+		return
+	}
+	s.sweepDurationSecondsSuccess.Observe(duration)
+	expiredAssetSweeperDeletedAssets.Add(float64(deleted))
+}
+
+// Run the ExpiredAssetSweeper until the context is cancelled. This
+// function may be launched as a routine in a program.Group.
+func (s *ExpiredAssetSweeper) Run(ctx context.Context, siblingsGroup, dependenciesGroup program.Group) error {
+	for {
+		s.sweep(ctx)
+
+		timer, t := s.clock.NewTimer(s.interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-t:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpiredAssetSweeper(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	assetStore := mock.NewMockEnumerableAssetStore(ctrl)
+	mockClock := mock.NewMockClock(ctrl)
+	freshnessPolicy := storage.NewFreshnessPolicy(mockClock, storage.FreshnessParameters{}, map[digest.InstanceName]storage.FreshnessParameters{
+		digest.MustNewInstanceName("a"): {StaleWhileRevalidate: 10 * time.Minute},
+		digest.MustNewInstanceName("b"): {StaleWhileRevalidate: 20 * time.Minute},
+	})
+	sweeper := storage.NewExpiredAssetSweeper(assetStore, freshnessPolicy, mockClock, time.Minute, time.Hour)
+	ctx, cancel := context.WithCancel(ctx)
+
+	// The first sweep should delete assets that expired more than
+	// the grace period ago, on top of the longest period during
+	// which stale assets may be returned.
+	mockClock.EXPECT().Now().Return(time.Unix(10000, 0))
+	assetStore.EXPECT().DeleteExpired(gomock.Any(), time.Unix(10000-3600-1200, 0)).Return(int64(3), nil)
+	mockClock.EXPECT().Now().Return(time.Unix(10001, 0))
+	timer1 := mock.NewMockTimer(ctrl)
+	ch1 := make(chan time.Time, 1)
+	ch1 <- time.Unix(10060, 0)
+	mockClock.EXPECT().NewTimer(time.Minute).Return(timer1, ch1)
+
+	// Failures should not cause the sweeper to terminate.
+	mockClock.EXPECT().Now().Return(time.Unix(10060, 0))
+	assetStore.EXPECT().DeleteExpired(gomock.Any(), time.Unix(10060-3600-1200, 0)).
+		Return(int64(0), status.Error(codes.Internal, "Database is corrupted"))
+	mockClock.EXPECT().Now().Return(time.Unix(10061, 0))
+	timer2 := mock.NewMockTimer(ctrl)
+	mockClock.EXPECT().NewTimer(time.Minute).DoAndReturn(func(d time.Duration) (clock.Timer, <-chan time.Time) {
+		cancel()
+		return timer2, make(chan time.Time)
+	})
+	timer2.EXPECT().Stop()
+
+	require.NoError(t, sweeper.Run(ctx, nil, nil))
+}
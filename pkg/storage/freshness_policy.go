@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// FreshnessPolicy determines for how long assets may be returned from
+// the asset cache. It is used both when creating assets, to compute
+// their expiration time, and when reading them, to determine whether
+// they have expired.
+type FreshnessPolicy interface {
+	// NewAsset creates an Asset for a digest that is stored as of
+	// now. The requested expiration time is optional, and is
+	// validated and limited according to the policy of the
+	// instance name.
+	NewAsset(instance digest.InstanceName, blobDigest *remoteexecution.Digest, requestedExpireAt *timestamppb.Timestamp) (*asset.Asset, error)
+
+	// GetFreshness returns whether an asset may be returned from the
+	// asset cache, and whether it should be fetched again in the
+	// background.
+	GetFreshness(instance digest.InstanceName, assetData *asset.Asset) Freshness
+
+	// GetMaximumStaleWhileRevalidate returns the longest amount of
+	// time after expiration during which assets of any instance
+	// name may still be returned. Expired assets must be retained
+	// for at least this long.
+	GetMaximumStaleWhileRevalidate() time.Duration
+}
+
+// Freshness of an asset, as reported by FreshnessPolicy.GetFreshness().
+type Freshness int
+
+const (
+	// FreshnessFresh indicates that the asset may be returned.
+	FreshnessFresh Freshness = iota
+	// FreshnessStale indicates that the asset may be returned, but
+	// that it has expired or is about to expire. It should be
+	// fetched again in the background.
+	FreshnessStale
+	// FreshnessExpired indicates that the asset must not be
+	// returned.
+	FreshnessExpired
+)
+
+// FreshnessParameters contain the limits that a FreshnessPolicy
+// applies to the assets of an instance name.
+type FreshnessParameters struct {
+	// Amount of time after which assets expire if no expiration time
+	// is requested. Zero if such assets don't expire.
+	DefaultTTL time.Duration
+	// Maximum amount of time after which assets expire. Requested
+	// expiration times beyond this limit are reduced. Zero if no
+	// limit applies.
+	MaximumTTL time.Duration
+	// Amount of time after expiration during which assets may still
+	// be returned, while they are fetched again in the background.
+	StaleWhileRevalidate time.Duration
+	// Amount of time before expiration during which assets are
+	// fetched again in the background, so that they are replaced
+	// before they expire.
+	RevalidateAhead time.Duration
+}
+
+type freshnessPolicy struct {
+	clock              clock.Clock
+	defaultParameters  FreshnessParameters
+	instanceParameters map[digest.InstanceName]FreshnessParameters
+}
+
+// NewFreshnessPolicy creates a FreshnessPolicy that applies
+// FreshnessParameters on a per instance name basis. Instance names
+// not present in the map use the default parameters.
+//
+// Expiration times are stored as the UNIX epoch for assets that don't
+// expire, as this is also how the asset cache reports unset
+// expiration times.
+func NewFreshnessPolicy(clock clock.Clock, defaultParameters FreshnessParameters, instanceParameters map[digest.InstanceName]FreshnessParameters) FreshnessPolicy {
+	return &freshnessPolicy{
+		clock:              clock,
+		defaultParameters:  defaultParameters,
+		instanceParameters: instanceParameters,
+	}
+}
+
+func (fp *freshnessPolicy) getParameters(instance digest.InstanceName) FreshnessParameters {
+	if parameters, ok := fp.instanceParameters[instance]; ok {
+		return parameters
+	}
+	return fp.defaultParameters
+}
+
+func isNeverExpiring(expireAt *timestamppb.Timestamp) bool {
+	return expireAt == nil || expireAt.AsTime().Equal(time.Unix(0, 0))
+}
+
+func (fp *freshnessPolicy) NewAsset(instance digest.InstanceName, blobDigest *remoteexecution.Digest, requestedExpireAt *timestamppb.Timestamp) (*asset.Asset, error) {
+	parameters := fp.getParameters(instance)
+	now := fp.clock.Now()
+
+	var expireAt time.Time
+	if isNeverExpiring(requestedExpireAt) {
+		if parameters.DefaultTTL > 0 {
+			expireAt = now.Add(parameters.DefaultTTL)
+		}
+	} else {
+		if err := requestedExpireAt.CheckValid(); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid expiration time")
+		}
+		expireAt = requestedExpireAt.AsTime()
+		if !expireAt.After(now) {
+			return nil, status.Errorf(codes.InvalidArgument, "Expiration time %s lies in the past", expireAt.UTC().Format(time.RFC3339))
+		}
+	}
+	if parameters.MaximumTTL > 0 {
+		if maximumExpireAt := now.Add(parameters.MaximumTTL); expireAt.IsZero() || expireAt.After(maximumExpireAt) {
+			expireAt = maximumExpireAt
+		}
+	}
+
+	assetData := &asset.Asset{
+		Digest:      blobDigest,
+		ExpireAt:    getDefaultTimestamp(),
+		LastUpdated: timestamppb.New(now),
+	}
+	if !expireAt.IsZero() {
+		assetData.ExpireAt = timestamppb.New(expireAt)
+	}
+	return assetData, nil
+}
+
+func (fp *freshnessPolicy) GetFreshness(instance digest.InstanceName, assetData *asset.Asset) Freshness {
+	if isNeverExpiring(assetData.ExpireAt) {
+		return FreshnessFresh
+	}
+	parameters := fp.getParameters(instance)
+	expireAt := assetData.ExpireAt.AsTime()
+	now := fp.clock.Now()
+	if !expireAt.Before(now) {
+		if parameters.RevalidateAhead > 0 && !expireAt.Add(-parameters.RevalidateAhead).After(now) {
+			return FreshnessStale
+		}
+		return FreshnessFresh
+	}
+	if expireAt.Add(parameters.StaleWhileRevalidate).After(now) {
+		return FreshnessStale
+	}
+	return FreshnessExpired
+}
+
+func (fp *freshnessPolicy) GetMaximumStaleWhileRevalidate() time.Duration {
+	maximum := fp.defaultParameters.StaleWhileRevalidate
+	for _, parameters := range fp.instanceParameters {
+		if parameters.StaleWhileRevalidate > maximum {
+			maximum = parameters.StaleWhileRevalidate
+		}
+	}
+	return maximum
+}
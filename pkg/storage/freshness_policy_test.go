@@ -0,0 +1,159 @@
+package storage_test
+
+import (
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestFreshnessPolicyNewAsset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClock := mock.NewMockClock(ctrl)
+	freshnessPolicy := storage.NewFreshnessPolicy(
+		mockClock,
+		storage.FreshnessParameters{},
+		map[digest.InstanceName]storage.FreshnessParameters{
+			digest.MustNewInstanceName("limited"): {
+				DefaultTTL: time.Hour,
+				MaximumTTL: 24 * time.Hour,
+			},
+			digest.MustNewInstanceName("capped"): {
+				MaximumTTL: 24 * time.Hour,
+			},
+		})
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+	defaultInstanceName := digest.MustNewInstanceName("")
+	limitedInstanceName := digest.MustNewInstanceName("limited")
+
+	t.Run("DefaultNeverExpires", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err := freshnessPolicy.NewAsset(defaultInstanceName, blobDigest, nil)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &asset.Asset{
+			Digest:      blobDigest,
+			ExpireAt:    timestamppb.New(time.Unix(0, 0)),
+			LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+		}, assetData)
+	})
+
+	t.Run("DefaultRequestedExpiration", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err := freshnessPolicy.NewAsset(defaultInstanceName, blobDigest, timestamppb.New(time.Unix(1000000, 0)))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &asset.Asset{
+			Digest:      blobDigest,
+			ExpireAt:    timestamppb.New(time.Unix(1000000, 0)),
+			LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+		}, assetData)
+	})
+
+	t.Run("DefaultTTL", func(t *testing.T) {
+		// Assets without an expiration time should receive the
+		// default TTL of the instance name.
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err := freshnessPolicy.NewAsset(limitedInstanceName, blobDigest, nil)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(4600, 0)), assetData.ExpireAt)
+
+		// The epoch is equivalent to not providing an expiration
+		// time.
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err = freshnessPolicy.NewAsset(limitedInstanceName, blobDigest, timestamppb.New(time.Unix(0, 0)))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(4600, 0)), assetData.ExpireAt)
+	})
+
+	t.Run("MaximumTTL", func(t *testing.T) {
+		// Expiration times beyond the maximum TTL should be
+		// clamped.
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err := freshnessPolicy.NewAsset(limitedInstanceName, blobDigest, timestamppb.New(time.Unix(1000000, 0)))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(87400, 0)), assetData.ExpireAt)
+
+		// Expiration times within the maximum TTL should be
+		// respected.
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err = freshnessPolicy.NewAsset(limitedInstanceName, blobDigest, timestamppb.New(time.Unix(2000, 0)))
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(2000, 0)), assetData.ExpireAt)
+
+		// Without a default TTL, assets should not be able to
+		// outlive the maximum TTL either.
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		assetData, err = freshnessPolicy.NewAsset(digest.MustNewInstanceName("capped"), blobDigest, nil)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(87400, 0)), assetData.ExpireAt)
+	})
+
+	t.Run("ExpirationInThePast", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		_, err := freshnessPolicy.NewAsset(defaultInstanceName, blobDigest, timestamppb.New(time.Unix(1000, 0)))
+		testutil.RequireEqualStatus(t, status.Error(codes.InvalidArgument, "Expiration time 1970-01-01T00:16:40Z lies in the past"), err)
+	})
+
+	t.Run("InvalidExpiration", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(1000, 0))
+		_, err := freshnessPolicy.NewAsset(defaultInstanceName, blobDigest, &timestamppb.Timestamp{Seconds: 1000000, Nanos: -1})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestFreshnessPolicyGetFreshness(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	mockClock := mock.NewMockClock(ctrl)
+	instanceName := digest.MustNewInstanceName("stale")
+	freshnessPolicy := storage.NewFreshnessPolicy(
+		mockClock,
+		storage.FreshnessParameters{},
+		map[digest.InstanceName]storage.FreshnessParameters{
+			instanceName: {
+				StaleWhileRevalidate: time.Hour,
+				RevalidateAhead:      time.Minute,
+			},
+		})
+	assetData := &asset.Asset{ExpireAt: timestamppb.New(time.Unix(10000, 0))}
+
+	t.Run("NeverExpires", func(t *testing.T) {
+		require.Equal(t, storage.FreshnessFresh, freshnessPolicy.GetFreshness(instanceName, &asset.Asset{}))
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		// Without stale-while-revalidate, assets are either fresh
+		// or expired.
+		mockClock.EXPECT().Now().Return(time.Unix(9990, 0))
+		require.Equal(t, storage.FreshnessFresh, freshnessPolicy.GetFreshness(digest.EmptyInstanceName, assetData))
+		mockClock.EXPECT().Now().Return(time.Unix(10001, 0))
+		require.Equal(t, storage.FreshnessExpired, freshnessPolicy.GetFreshness(digest.EmptyInstanceName, assetData))
+	})
+
+	t.Run("StaleWhileRevalidate", func(t *testing.T) {
+		mockClock.EXPECT().Now().Return(time.Unix(9900, 0))
+		require.Equal(t, storage.FreshnessFresh, freshnessPolicy.GetFreshness(instanceName, assetData))
+
+		// Assets that are about to expire should be revalidated.
+		mockClock.EXPECT().Now().Return(time.Unix(9990, 0))
+		require.Equal(t, storage.FreshnessStale, freshnessPolicy.GetFreshness(instanceName, assetData))
+
+		// Assets that have expired recently may still be
+		// returned.
+		mockClock.EXPECT().Now().Return(time.Unix(13000, 0))
+		require.Equal(t, storage.FreshnessStale, freshnessPolicy.GetFreshness(instanceName, assetData))
+
+		mockClock.EXPECT().Now().Return(time.Unix(13600, 0))
+		require.Equal(t, storage.FreshnessExpired, freshnessPolicy.GetFreshness(instanceName, assetData))
+	})
+}
@@ -0,0 +1,47 @@
+package storage
+
+import (
+	"context"
+	"log"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+type indexingAssetStore struct {
+	AssetStore
+	referenceIndex ReferenceIndex
+}
+
+// NewIndexingAssetStore creates a decorator for AssetStore that adds
+// the references of all assets that are stored to a ReferenceIndex.
+//
+// Entries are not removed from the index when assets are deleted or
+// overwritten. Users of the index should call Get() against the asset
+// store to determine whether a reference still resolves to the digest.
+//
+// The values of qualifiers that may contain credentials are redacted
+// before references are added to the index. Failures to update the
+// index are logged, as opposed to causing the asset to be reported as
+// not stored.
+func NewIndexingAssetStore(assetStore AssetStore, referenceIndex ReferenceIndex) AssetStore {
+	return &indexingAssetStore{
+		AssetStore:     assetStore,
+		referenceIndex: referenceIndex,
+	}
+}
+
+func (as *indexingAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+	if err := as.AssetStore.Put(ctx, ref, data, instance); err != nil {
+		return err
+	}
+	redactedRef := &asset.AssetReference{
+		Uris:       ref.Uris,
+		Qualifiers: qualifier.Redact(ref.Qualifiers),
+	}
+	if err := as.referenceIndex.Add(ctx, data.Digest, redactedRef, instance); err != nil {
+		log.Printf("Failed to add asset %s to reference index: %v", ref.Uris, err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestIndexingAssetStorePut(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName, err := digest.NewInstanceName("foo")
+	require.NoError(t, err)
+
+	blobDigest := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"}, []*remoteasset.Qualifier{})
+	assetData := storage.NewAsset(blobDigest, timestamppb.Now())
+
+	baseStore := mock.NewMockAssetStore(ctrl)
+	referenceIndex := mock.NewMockReferenceIndex(ctrl)
+	assetStore := storage.NewIndexingAssetStore(baseStore, referenceIndex)
+
+	t.Run("Success", func(t *testing.T) {
+		baseStore.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+		referenceIndex.EXPECT().Add(ctx, blobDigest, testutil.EqProto(t, assetRef), instanceName)
+
+		require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("StoreFailure", func(t *testing.T) {
+		// The index should not be updated if the asset could
+		// not be stored.
+		baseStore.EXPECT().Put(ctx, assetRef, assetData, instanceName).
+			Return(status.Error(codes.Unavailable, "Server offline"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Server offline"),
+			assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("IndexFailure", func(t *testing.T) {
+		// The asset has been stored, meaning that failing to
+		// update the index should not cause Put() to fail.
+		baseStore.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+		referenceIndex.EXPECT().Add(ctx, blobDigest, testutil.EqProto(t, assetRef), instanceName).
+			Return(status.Error(codes.Unavailable, "Server offline"))
+
+		require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("SecretQualifiers", func(t *testing.T) {
+		// Credentials should not be written to the index.
+		secretRef := storage.NewAssetReference(
+			[]string{"https://example.com/example.txt"},
+			[]*remoteasset.Qualifier{
+				{Name: "auth.basic.password", Value: "hunter2"},
+				{Name: "checksum.sri", Value: "sha256-abc"},
+			})
+		baseStore.EXPECT().Put(ctx, secretRef, assetData, instanceName)
+		referenceIndex.EXPECT().Add(ctx, blobDigest, testutil.EqProto(t, storage.NewAssetReference(
+			[]string{"https://example.com/example.txt"},
+			[]*remoteasset.Qualifier{
+				{Name: "auth.basic.password", Value: "[redacted]"},
+				{Name: "checksum.sri", Value: "sha256-abc"},
+			})), instanceName)
+
+		require.NoError(t, assetStore.Put(ctx, secretRef, assetData, instanceName))
+	})
+}
@@ -0,0 +1,188 @@
+package storage
+
+import (
+	"container/heap"
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"google.golang.org/protobuf/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type memoryAssetStoreEntry struct {
+	key       digest.Digest
+	asset     *asset.Asset
+	sizeBytes int64
+
+	// The time at which the asset is discarded, and its index in
+	// memoryAssetStore.expirationHeap. The index is -1 for assets
+	// that don't expire.
+	retainUntil         time.Time
+	expirationHeapIndex int
+}
+
+// memoryAssetStoreExpirationHeap is a heap of entries that expire,
+// ordered by the time at which they are discarded.
+type memoryAssetStoreExpirationHeap []*memoryAssetStoreEntry
+
+func (h memoryAssetStoreExpirationHeap) Len() int {
+	return len(h)
+}
+
+func (h memoryAssetStoreExpirationHeap) Less(i, j int) bool {
+	return h[i].retainUntil.Before(h[j].retainUntil)
+}
+
+func (h memoryAssetStoreExpirationHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].expirationHeapIndex = i
+	h[j].expirationHeapIndex = j
+}
+
+func (h *memoryAssetStoreExpirationHeap) Push(x any) {
+	entry := x.(*memoryAssetStoreEntry)
+	entry.expirationHeapIndex = len(*h)
+	*h = append(*h, entry)
+}
+
+func (h *memoryAssetStoreExpirationHeap) Pop() any {
+	old := *h
+	entry := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	entry.expirationHeapIndex = -1
+	return entry
+}
+
+type memoryAssetStore struct {
+	maximumEntries   int
+	maximumSizeBytes int64
+	clock            clock.Clock
+	retentionPeriod  time.Duration
+
+	lock           sync.Mutex
+	entries        map[digest.Digest]*list.Element
+	lruList        list.List
+	expirationHeap memoryAssetStoreExpirationHeap
+	totalSizeBytes int64
+}
+
+// NewMemoryAssetStore creates an AssetStore that keeps assets in
+// memory. When either the number of assets or their total size exceeds
+// the provided limits, the least recently used assets are discarded.
+//
+// Expired assets are still returned during the retention period, as it
+// is up to the caller to determine whether they may be served while
+// stale. The retention period should thus be at least as long as the
+// maximum stale-while-revalidate duration of the FreshnessPolicy.
+// Afterwards, assets are discarded.
+//
+// Assets are lost when the process restarts, making this store mainly
+// useful for testing, or as a fast cache in front of persistent
+// storage.
+func NewMemoryAssetStore(maximumEntries int, maximumSizeBytes int64, clock clock.Clock, retentionPeriod time.Duration) AssetStore {
+	return &memoryAssetStore{
+		maximumEntries:   maximumEntries,
+		maximumSizeBytes: maximumSizeBytes,
+		clock:            clock,
+		retentionPeriod:  retentionPeriod,
+		entries:          map[digest.Digest]*list.Element{},
+	}
+}
+
+func (as *memoryAssetStore) removeElement(element *list.Element) {
+	entry := as.lruList.Remove(element).(*memoryAssetStoreEntry)
+	delete(as.entries, entry.key)
+	if entry.expirationHeapIndex >= 0 {
+		heap.Remove(&as.expirationHeap, entry.expirationHeapIndex)
+	}
+	as.totalSizeBytes -= entry.sizeBytes
+}
+
+// removeExpired discards all assets whose retention period has passed,
+// so that they don't take up capacity until evicted.
+func (as *memoryAssetStore) removeExpired() {
+	now := as.clock.Now()
+	for len(as.expirationHeap) > 0 && as.expirationHeap[0].retainUntil.Before(now) {
+		as.removeElement(as.entries[as.expirationHeap[0].key])
+	}
+}
+
+func (as *memoryAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+	key, err := AssetReferenceToDigest(ref, instance)
+	if err != nil {
+		return nil, err
+	}
+
+	as.lock.Lock()
+	defer as.lock.Unlock()
+
+	as.removeExpired()
+	element, ok := as.entries[key]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "Asset not found")
+	}
+	entry := element.Value.(*memoryAssetStoreEntry)
+	as.lruList.MoveToBack(element)
+	return proto.Clone(entry.asset).(*asset.Asset), nil
+}
+
+func (as *memoryAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+	key, err := AssetReferenceToDigest(ref, instance)
+	if err != nil {
+		return err
+	}
+
+	// Account for both the asset and the reference under which it
+	// is stored, as the size of the latter is unbounded.
+	entry := &memoryAssetStoreEntry{
+		key:                 key,
+		asset:               proto.Clone(data).(*asset.Asset),
+		sizeBytes:           int64(proto.Size(data)) + key.GetSizeBytes(),
+		expirationHeapIndex: -1,
+	}
+	if entry.sizeBytes > as.maximumSizeBytes {
+		return status.Errorf(codes.InvalidArgument, "Asset is %d bytes in size, while the memory asset store is limited to %d bytes", entry.sizeBytes, as.maximumSizeBytes)
+	}
+
+	as.lock.Lock()
+	defer as.lock.Unlock()
+
+	if element, ok := as.entries[key]; ok {
+		as.removeElement(element)
+	}
+	as.entries[key] = as.lruList.PushBack(entry)
+	as.totalSizeBytes += entry.sizeBytes
+	if !isNeverExpiring(data.ExpireAt) {
+		entry.retainUntil = data.ExpireAt.AsTime().Add(as.retentionPeriod)
+		heap.Push(&as.expirationHeap, entry)
+	}
+
+	as.removeExpired()
+	for len(as.entries) > as.maximumEntries || as.totalSizeBytes > as.maximumSizeBytes {
+		as.removeElement(as.lruList.Front())
+	}
+	return nil
+}
+
+func (as *memoryAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	key, err := AssetReferenceToDigest(ref, instance)
+	if err != nil {
+		return err
+	}
+
+	as.lock.Lock()
+	defer as.lock.Unlock()
+
+	if element, ok := as.entries[key]; ok {
+		as.removeElement(element)
+	}
+	return nil
+}
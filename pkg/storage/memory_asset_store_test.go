@@ -0,0 +1,148 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMemoryAssetStore(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName, err := digest.NewInstanceName("foo")
+	require.NoError(t, err)
+
+	blobDigest := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	ref1 := storage.NewAssetReference([]string{"https://example.com/1.txt"}, []*remoteasset.Qualifier{})
+	ref2 := storage.NewAssetReference([]string{"https://example.com/2.txt"}, []*remoteasset.Qualifier{})
+	ref3 := storage.NewAssetReference([]string{"https://example.com/3.txt"}, []*remoteasset.Qualifier{})
+	assetData := storage.NewAsset(blobDigest, timestamppb.New(time.Unix(0, 0)))
+
+	// Assets that don't expire should not depend on the time.
+	clock := mock.NewMockClock(ctrl)
+	clock.EXPECT().Now().Return(time.Unix(1000, 0)).AnyTimes()
+
+	t.Run("PutGetDelete", func(t *testing.T) {
+		assetStore := storage.NewMemoryAssetStore(10, 1024*1024, clock, time.Minute)
+
+		_, err := assetStore.Get(ctx, ref1, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+
+		require.NoError(t, assetStore.Put(ctx, ref1, assetData, instanceName))
+		storedAsset, err := assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, assetData, storedAsset)
+
+		// Assets should be stored separately per instance name.
+		_, err = assetStore.Get(ctx, ref1, digest.EmptyInstanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+
+		require.NoError(t, assetStore.Delete(ctx, ref1, instanceName))
+		_, err = assetStore.Get(ctx, ref1, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+	})
+
+	t.Run("Expiration", func(t *testing.T) {
+		// Expired assets should still be returned during the
+		// retention period, as FreshnessPolicy may permit
+		// serving them while stale.
+		assetStore := storage.NewMemoryAssetStore(10, 1024*1024, clock, time.Minute)
+
+		require.NoError(t, assetStore.Put(ctx, ref1, storage.NewAsset(blobDigest, timestamppb.New(time.Unix(999, 0))), instanceName))
+		require.NoError(t, assetStore.Put(ctx, ref2, storage.NewAsset(blobDigest, timestamppb.New(time.Unix(1001, 0))), instanceName))
+
+		storedAsset, err := assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, timestamppb.New(time.Unix(999, 0)), storedAsset.ExpireAt)
+		_, err = assetStore.Get(ctx, ref2, instanceName)
+		require.NoError(t, err)
+	})
+
+	t.Run("Retention", func(t *testing.T) {
+		// Assets should be discarded once their retention
+		// period has passed, without taking up capacity.
+		assetStore := storage.NewMemoryAssetStore(2, 1024*1024, clock, time.Minute)
+
+		require.NoError(t, assetStore.Put(ctx, ref2, storage.NewAsset(blobDigest, timestamppb.New(time.Unix(941, 0))), instanceName))
+		require.NoError(t, assetStore.Put(ctx, ref1, storage.NewAsset(blobDigest, timestamppb.New(time.Unix(939, 0))), instanceName))
+		require.NoError(t, assetStore.Put(ctx, ref3, assetData, instanceName))
+
+		_, err := assetStore.Get(ctx, ref1, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+		_, err = assetStore.Get(ctx, ref2, instanceName)
+		require.NoError(t, err)
+		_, err = assetStore.Get(ctx, ref3, instanceName)
+		require.NoError(t, err)
+	})
+
+	t.Run("EvictionByEntries", func(t *testing.T) {
+		assetStore := storage.NewMemoryAssetStore(2, 1024*1024, clock, time.Minute)
+
+		require.NoError(t, assetStore.Put(ctx, ref1, assetData, instanceName))
+		require.NoError(t, assetStore.Put(ctx, ref2, assetData, instanceName))
+
+		// Accessing the first asset should cause the second one
+		// to be evicted instead.
+		_, err := assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		require.NoError(t, assetStore.Put(ctx, ref3, assetData, instanceName))
+
+		_, err = assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		_, err = assetStore.Get(ctx, ref2, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+		_, err = assetStore.Get(ctx, ref3, instanceName)
+		require.NoError(t, err)
+	})
+
+	t.Run("EvictionBySize", func(t *testing.T) {
+		// Sized such that only a single asset fits.
+		assetStore := storage.NewMemoryAssetStore(10, 150, clock, time.Minute)
+
+		require.NoError(t, assetStore.Put(ctx, ref1, assetData, instanceName))
+		require.NoError(t, assetStore.Put(ctx, ref2, assetData, instanceName))
+
+		_, err := assetStore.Get(ctx, ref1, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+		_, err = assetStore.Get(ctx, ref2, instanceName)
+		require.NoError(t, err)
+	})
+
+	t.Run("TooLarge", func(t *testing.T) {
+		assetStore := storage.NewMemoryAssetStore(10, 10, clock, time.Minute)
+
+		err := assetStore.Put(ctx, ref1, assetData, instanceName)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Isolation", func(t *testing.T) {
+		// Modifying assets after storing or retrieving them
+		// should not affect the contents of the store.
+		assetStore := storage.NewMemoryAssetStore(10, 1024*1024, clock, time.Minute)
+
+		putAsset := &asset.Asset{Digest: blobDigest}
+		require.NoError(t, assetStore.Put(ctx, ref1, putAsset, instanceName))
+		putAsset.Digest = nil
+
+		getAsset, err := assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		getAsset.Digest = nil
+
+		getAsset, err = assetStore.Get(ctx, ref1, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, &asset.Asset{Digest: blobDigest}, getAsset)
+	})
+}
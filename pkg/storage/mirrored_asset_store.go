@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"context"
+	"log"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/protobuf/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type mirroredAssetStore struct {
+	storeA AssetStore
+	storeB AssetStore
+}
+
+// NewMirroredAssetStore creates an AssetStore that stores all assets
+// in two stores. Get() queries both stores, succeeding as long as
+// either of them is able to return the asset. If the stores disagree
+// on the contents of an asset, the store lacking the asset or holding
+// the least recently updated copy is repaired.
+func NewMirroredAssetStore(storeA, storeB AssetStore) AssetStore {
+	return &mirroredAssetStore{
+		storeA: storeA,
+		storeB: storeB,
+	}
+}
+
+type mirroredAssetStoreGetResult struct {
+	asset *asset.Asset
+	err   error
+}
+
+// repairMirroredAsset copies an asset into a store that is missing it or holds an
+// outdated copy. Failing to do so does not affect the outcome of the
+// request, as the asset can still be served from the other store.
+func repairMirroredAsset(ctx context.Context, store AssetStore, storeName string, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) {
+	if err := store.Put(ctx, ref, data, instance); err != nil {
+		log.Printf("Failed to repair asset %s in store %s: %v", ref.Uris, storeName, err)
+	}
+}
+
+func (as *mirroredAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+	resultB := make(chan mirroredAssetStoreGetResult, 1)
+	go func() {
+		assetData, err := as.storeB.Get(ctx, ref, instance)
+		resultB <- mirroredAssetStoreGetResult{asset: assetData, err: err}
+	}()
+	assetA, errA := as.storeA.Get(ctx, ref, instance)
+	b := <-resultB
+	assetB, errB := b.asset, b.err
+
+	switch {
+	case errA == nil && errB == nil:
+		if proto.Equal(assetA, assetB) {
+			return assetA, nil
+		}
+		// Prefer the most recently updated copy.
+		if assetB.LastUpdated.AsTime().After(assetA.LastUpdated.AsTime()) {
+			repairMirroredAsset(ctx, as.storeA, "A", ref, assetB, instance)
+			return assetB, nil
+		}
+		repairMirroredAsset(ctx, as.storeB, "B", ref, assetA, instance)
+		return assetA, nil
+	case errA == nil:
+		if status.Code(errB) == codes.NotFound {
+			repairMirroredAsset(ctx, as.storeB, "B", ref, assetA, instance)
+		}
+		return assetA, nil
+	case errB == nil:
+		if status.Code(errA) == codes.NotFound {
+			repairMirroredAsset(ctx, as.storeA, "A", ref, assetB, instance)
+		}
+		return assetB, nil
+	case status.Code(errA) != codes.NotFound:
+		return nil, util.StatusWrap(errA, "Store A")
+	case status.Code(errB) != codes.NotFound:
+		return nil, util.StatusWrap(errB, "Store B")
+	default:
+		return nil, status.Error(codes.NotFound, "Asset not found in either store")
+	}
+}
+
+func (as *mirroredAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+	resultB := make(chan error, 1)
+	go func() {
+		resultB <- as.storeB.Put(ctx, ref, data, instance)
+	}()
+	errA := as.storeA.Put(ctx, ref, data, instance)
+	errB := <-resultB
+	if errA != nil {
+		return util.StatusWrap(errA, "Store A")
+	}
+	if errB != nil {
+		return util.StatusWrap(errB, "Store B")
+	}
+	return nil
+}
+
+func (as *mirroredAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	resultB := make(chan error, 1)
+	go func() {
+		resultB <- as.storeB.Delete(ctx, ref, instance)
+	}()
+	errA := as.storeA.Delete(ctx, ref, instance)
+	errB := <-resultB
+	if errA != nil {
+		return util.StatusWrap(errA, "Store A")
+	}
+	if errB != nil {
+		return util.StatusWrap(errB, "Store B")
+	}
+	return nil
+}
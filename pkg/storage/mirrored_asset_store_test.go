@@ -0,0 +1,126 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMirroredAssetStoreGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"}, nil)
+	oldAsset := &asset.Asset{
+		Digest:      &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+		LastUpdated: timestamppb.New(time.Unix(1000, 0)),
+	}
+	newAsset := &asset.Asset{
+		Digest:      &remoteexecution.Digest{Hash: "aec070645fe53ee3b3763059376134f058cc337247c978add178b6ccdfb0019f", SizeBytes: 222},
+		LastUpdated: timestamppb.New(time.Unix(2000, 0)),
+	}
+
+	storeA := mock.NewMockAssetStore(ctrl)
+	storeB := mock.NewMockAssetStore(ctrl)
+	assetStore := storage.NewMirroredAssetStore(storeA, storeB)
+
+	t.Run("Consistent", func(t *testing.T) {
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(oldAsset, nil)
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(oldAsset, nil)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, oldAsset, storedAsset)
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		// The most recently updated copy should win.
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(oldAsset, nil)
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(newAsset, nil)
+		storeA.EXPECT().Put(ctx, assetRef, newAsset, instanceName)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, newAsset, storedAsset)
+	})
+
+	t.Run("MissingInB", func(t *testing.T) {
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(oldAsset, nil)
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		storeB.EXPECT().Put(ctx, assetRef, oldAsset, instanceName)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, oldAsset, storedAsset)
+	})
+
+	t.Run("UnavailableA", func(t *testing.T) {
+		// Stores that fail should not be repaired, but the
+		// asset can still be served from the other one.
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.Unavailable, "Server offline"))
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(oldAsset, nil)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, oldAsset, storedAsset)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+
+		_, err := assetStore.Get(ctx, assetRef, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found in either store"), err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		storeA.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		storeB.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.Unavailable, "Server offline"))
+
+		_, err := assetStore.Get(ctx, assetRef, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Store B: Server offline"), err)
+	})
+}
+
+func TestMirroredAssetStorePut(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"}, nil)
+	assetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+	}
+
+	storeA := mock.NewMockAssetStore(ctrl)
+	storeB := mock.NewMockAssetStore(ctrl)
+	assetStore := storage.NewMirroredAssetStore(storeA, storeB)
+
+	t.Run("Success", func(t *testing.T) {
+		storeA.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+		storeB.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+
+		require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		storeA.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+		storeB.EXPECT().Put(ctx, assetRef, assetData, instanceName).Return(status.Error(codes.Unavailable, "Server offline"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Store B: Server offline"),
+			assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+}
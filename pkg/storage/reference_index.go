@@ -0,0 +1,18 @@
+package storage
+
+import (
+	"context"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+)
+
+// ReferenceIndex maintains a mapping from the digests of blobs and
+// directories to the references of the assets that refer to them. This
+// makes it possible to determine which URIs and qualifiers resolve to
+// a given object.
+type ReferenceIndex interface {
+	Add(ctx context.Context, blobDigest *remoteexecution.Digest, ref *asset.AssetReference, instance digest.InstanceName) error
+	Get(ctx context.Context, blobDigest *remoteexecution.Digest, instance digest.InstanceName) ([]*asset.AssetReference, error)
+}
@@ -0,0 +1,380 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-storage/pkg/clock"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The schema of the database. Assets are keyed by the wire format of
+// their reference. The URIs of assets are stored in a separate table,
+// so that assets can be searched by URI prefix.
+var sqliteAssetStoreSchema = []string{
+	`CREATE TABLE IF NOT EXISTS assets (
+		id INTEGER PRIMARY KEY,
+		instance_name TEXT NOT NULL,
+		reference BLOB NOT NULL,
+		qualifiers TEXT NOT NULL,
+		digest_hash TEXT NOT NULL,
+		digest_size_bytes INTEGER NOT NULL,
+		expire_at INTEGER,
+		last_updated INTEGER,
+		origin_uri TEXT NOT NULL,
+		UNIQUE (instance_name, reference)
+	)`,
+	`CREATE INDEX IF NOT EXISTS assets_expire_at ON assets (expire_at)`,
+	`CREATE TABLE IF NOT EXISTS asset_uris (
+		asset_id INTEGER NOT NULL REFERENCES assets (id),
+		position INTEGER NOT NULL,
+		uri TEXT NOT NULL,
+		PRIMARY KEY (asset_id, position)
+	)`,
+	`CREATE INDEX IF NOT EXISTS asset_uris_uri ON asset_uris (uri)`,
+}
+
+// The condition under which assets are considered to be expired. An
+// expiration time of zero corresponds to the UNIX epoch, which
+// indicates that the asset does not expire.
+const sqliteAssetStoreExpiredCondition = "expire_at IS NOT NULL AND expire_at != 0 AND expire_at < ?"
+
+type sqliteAssetStore struct {
+	db    *sql.DB
+	clock clock.Clock
+}
+
+// NewSQLiteAssetStore creates an AssetStore that stores assets in a
+// SQLite database. As opposed to the stores backed by a BlobAccess,
+// all properties of assets are stored in separate columns, making it
+// possible to enumerate, search and expire assets. The values of
+// qualifiers that may contain credentials are only stored as a hash,
+// meaning that they are not returned by List() either.
+//
+// The tables of the database are created if they do not exist.
+func NewSQLiteAssetStore(ctx context.Context, db *sql.DB, clock clock.Clock) (EnumerableAssetStore, error) {
+	for _, statement := range sqliteAssetStoreSchema {
+		if _, err := db.ExecContext(ctx, statement); err != nil {
+			return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to create database schema")
+		}
+	}
+	return &sqliteAssetStore{
+		db:    db,
+		clock: clock,
+	}, nil
+}
+
+type sqliteQualifier struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// hashSecretQualifiers returns a copy of a reference, in which the
+// values of qualifiers that may contain credentials are replaced by
+// their SHA-256 hash. This prevents credentials from being written to
+// disk, while still causing references with different credentials to
+// be stored separately.
+func hashSecretQualifiers(ref *asset.AssetReference) *asset.AssetReference {
+	qualifiers := make([]*remoteasset.Qualifier, 0, len(ref.Qualifiers))
+	for _, q := range ref.Qualifiers {
+		if qualifier.SecretQualifiers.Contains(q.Name) {
+			hash := sha256.Sum256([]byte(q.Value))
+			q = &remoteasset.Qualifier{Name: q.Name, Value: "sha256:" + hex.EncodeToString(hash[:])}
+		}
+		qualifiers = append(qualifiers, q)
+	}
+	return &asset.AssetReference{Uris: ref.Uris, Qualifiers: qualifiers}
+}
+
+func marshalSQLiteReference(ref *asset.AssetReference) ([]byte, string, error) {
+	ref = hashSecretQualifiers(ref)
+	reference, err := proto.MarshalOptions{Deterministic: true}.Marshal(ref)
+	if err != nil {
+		return nil, "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to marshal asset reference")
+	}
+	qualifiers := make([]sqliteQualifier, 0, len(ref.Qualifiers))
+	for _, qualifier := range ref.Qualifiers {
+		qualifiers = append(qualifiers, sqliteQualifier{
+			Name:  qualifier.Name,
+			Value: qualifier.Value,
+		})
+	}
+	qualifiersJSON, err := json.Marshal(qualifiers)
+	if err != nil {
+		return nil, "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Failed to marshal qualifiers")
+	}
+	return reference, string(qualifiersJSON), nil
+}
+
+func timestampToSQLite(t *timestamppb.Timestamp) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+	return sql.NullInt64{Int64: t.AsTime().UnixNano(), Valid: true}
+}
+
+func timestampFromSQLite(t sql.NullInt64) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(time.Unix(0, t.Int64))
+}
+
+// sqliteAssetColumns are the columns needed by scanAsset().
+const sqliteAssetColumns = "digest_hash, digest_size_bytes, expire_at, last_updated, origin_uri"
+
+func scanAsset(scan func(dest ...any) error, extraDest ...any) (*asset.Asset, error) {
+	var digestHash, originURI string
+	var digestSizeBytes int64
+	var expireAt, lastUpdated sql.NullInt64
+	if err := scan(append(extraDest, &digestHash, &digestSizeBytes, &expireAt, &lastUpdated, &originURI)...); err != nil {
+		return nil, err
+	}
+	return &asset.Asset{
+		Digest: &remoteexecution.Digest{
+			Hash:      digestHash,
+			SizeBytes: digestSizeBytes,
+		},
+		ExpireAt:    timestampFromSQLite(expireAt),
+		LastUpdated: timestampFromSQLite(lastUpdated),
+		OriginUri:   originURI,
+	}, nil
+}
+
+func (as *sqliteAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+	reference, _, err := marshalSQLiteReference(ref)
+	if err != nil {
+		return nil, err
+	}
+	assetData, err := scanAsset(as.db.QueryRowContext(
+		ctx,
+		"SELECT "+sqliteAssetColumns+" FROM assets WHERE instance_name = ? AND reference = ?",
+		instance.String(),
+		reference,
+	).Scan)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "Asset not found")
+		}
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to query asset")
+	}
+	return assetData, nil
+}
+
+func (as *sqliteAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+	reference, qualifiers, err := marshalSQLiteReference(ref)
+	if err != nil {
+		return err
+	}
+
+	tx, err := as.db.BeginTx(ctx, nil)
+	if err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	var assetID int64
+	if err := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO assets (instance_name, reference, qualifiers, digest_hash, digest_size_bytes, expire_at, last_updated, origin_uri)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (instance_name, reference) DO UPDATE SET
+			digest_hash = excluded.digest_hash,
+			digest_size_bytes = excluded.digest_size_bytes,
+			expire_at = excluded.expire_at,
+			last_updated = excluded.last_updated,
+			origin_uri = excluded.origin_uri
+		RETURNING id`,
+		instance.String(),
+		reference,
+		qualifiers,
+		data.Digest.GetHash(),
+		data.Digest.GetSizeBytes(),
+		timestampToSQLite(data.ExpireAt),
+		timestampToSQLite(data.LastUpdated),
+		data.OriginUri,
+	).Scan(&assetID); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to store asset")
+	}
+
+	// The URIs are part of the reference, meaning they only need to
+	// be stored when the asset is created.
+	for i, uri := range ref.Uris {
+		if _, err := tx.ExecContext(
+			ctx,
+			"INSERT OR IGNORE INTO asset_uris (asset_id, position, uri) VALUES (?, ?, ?)",
+			assetID,
+			i,
+			uri,
+		); err != nil {
+			return util.StatusWrapWithCode(err, codes.Internal, "Failed to store asset URIs")
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return util.StatusWrapWithCode(err, codes.Internal, "Failed to commit transaction")
+	}
+	return nil
+}
+
+// deleteWhere deletes all assets matching a condition, returning the
+// number of assets deleted.
+func (as *sqliteAssetStore) deleteWhere(ctx context.Context, condition string, args ...any) (int64, error) {
+	tx, err := as.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to start transaction")
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM asset_uris WHERE asset_id IN (SELECT id FROM assets WHERE "+condition+")", args...); err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to delete asset URIs")
+	}
+	result, err := tx.ExecContext(ctx, "DELETE FROM assets WHERE "+condition, args...)
+	if err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to delete assets")
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to obtain number of deleted assets")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, util.StatusWrapWithCode(err, codes.Internal, "Failed to commit transaction")
+	}
+	return deleted, nil
+}
+
+func (as *sqliteAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	reference, _, err := marshalSQLiteReference(ref)
+	if err != nil {
+		return err
+	}
+	_, err = as.deleteWhere(ctx, "instance_name = ? AND reference = ?", instance.String(), reference)
+	return err
+}
+
+// getPrefixUpperBound returns the smallest string that is larger than
+// all strings having a given prefix. This permits prefix searches to
+// make use of indices. False is returned if no such string exists.
+func getPrefixUpperBound(prefix string) (string, bool) {
+	b := []byte(prefix)
+	for i := len(b) - 1; i >= 0; i-- {
+		if b[i] != 0xff {
+			b[i]++
+			return string(b[:i+1]), true
+		}
+	}
+	return "", false
+}
+
+func (as *sqliteAssetStore) List(ctx context.Context, instance digest.InstanceName, uriPrefix, pageToken string, pageSize int) ([]EnumeratedAsset, string, error) {
+	if pageSize <= 0 {
+		return nil, "", status.Error(codes.InvalidArgument, "Page size must be positive")
+	}
+	var lastID int64
+	if pageToken != "" {
+		var err error
+		lastID, err = strconv.ParseInt(pageToken, 10, 64)
+		if err != nil {
+			return nil, "", util.StatusWrapWithCode(err, codes.InvalidArgument, "Invalid page token")
+		}
+	}
+
+	query := "SELECT id, reference, " + sqliteAssetColumns + " FROM assets WHERE instance_name = ? AND id > ?"
+	args := []any{instance.String(), lastID}
+	if uriPrefix != "" {
+		query += " AND EXISTS (SELECT 1 FROM asset_uris WHERE asset_id = assets.id AND uri >= ?"
+		args = append(args, uriPrefix)
+		if upperBound, ok := getPrefixUpperBound(uriPrefix); ok {
+			query += " AND uri < ?"
+			args = append(args, upperBound)
+		}
+		query += ")"
+	}
+	// Request one additional row to determine whether another page
+	// is present.
+	query += " ORDER BY id LIMIT ?"
+	args = append(args, pageSize+1)
+
+	rows, err := as.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, "", util.StatusWrapWithCode(err, codes.Internal, "Failed to query assets")
+	}
+	defer rows.Close()
+
+	var assets []EnumeratedAsset
+	nextPageToken := ""
+	for rows.Next() {
+		if len(assets) == pageSize {
+			nextPageToken = strconv.FormatInt(lastID, 10)
+			break
+		}
+		var reference []byte
+		assetData, err := scanAsset(rows.Scan, &lastID, &reference)
+		if err != nil {
+			return nil, "", util.StatusWrapWithCode(err, codes.Internal, "Failed to read asset")
+		}
+		var ref asset.AssetReference
+		if err := proto.Unmarshal(reference, &ref); err != nil {
+			return nil, "", util.StatusWrapWithCode(err, codes.Internal, "Failed to unmarshal asset reference")
+		}
+		assets = append(assets, EnumeratedAsset{
+			Reference: &ref,
+			Asset:     assetData,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", util.StatusWrapWithCode(err, codes.Internal, "Failed to read assets")
+	}
+	return assets, nextPageToken, nil
+}
+
+func (as *sqliteAssetStore) DeleteExpired(ctx context.Context, expiredBefore time.Time) (int64, error) {
+	return as.deleteWhere(ctx, sqliteAssetStoreExpiredCondition, expiredBefore.UnixNano())
+}
+
+func (as *sqliteAssetStore) GetStatistics(ctx context.Context, instance digest.InstanceName) (*AssetStoreStatistics, error) {
+	var statistics AssetStoreStatistics
+	var oldestLastUpdated, newestLastUpdated sql.NullInt64
+	if err := as.db.QueryRowContext(
+		ctx,
+		`SELECT
+			COUNT(*),
+			COUNT(CASE WHEN `+sqliteAssetStoreExpiredCondition+` THEN 1 END),
+			COUNT(DISTINCT digest_hash || '-' || digest_size_bytes),
+			MIN(last_updated),
+			MAX(last_updated)
+		FROM assets WHERE instance_name = ?`,
+		as.clock.Now().UnixNano(),
+		instance.String(),
+	).Scan(
+		&statistics.Assets,
+		&statistics.ExpiredAssets,
+		&statistics.DistinctDigests,
+		&oldestLastUpdated,
+		&newestLastUpdated,
+	); err != nil {
+		return nil, util.StatusWrapWithCode(err, codes.Internal, "Failed to query statistics")
+	}
+	if oldestLastUpdated.Valid {
+		statistics.OldestLastUpdated = time.Unix(0, oldestLastUpdated.Int64)
+		statistics.NewestLastUpdated = time.Unix(0, newestLastUpdated.Int64)
+	}
+	return &statistics, nil
+}
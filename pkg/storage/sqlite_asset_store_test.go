@@ -0,0 +1,248 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	_ "modernc.org/sqlite"
+)
+
+func newTestSQLiteAssetStore(ctx context.Context, t *testing.T, clock *mock.MockClock) storage.EnumerableAssetStore {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// Every connection to an in-memory database has its own copy
+	// of the database.
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	assetStore, err := storage.NewSQLiteAssetStore(ctx, db, clock)
+	require.NoError(t, err)
+	return assetStore
+}
+
+func TestSQLiteAssetStorePutGetDelete(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	assetRef := storage.NewAssetReference(
+		[]string{"https://example.com/a.txt", "https://mirror.example.com/a.txt"},
+		[]*remoteasset.Qualifier{{Name: "checksum.sri", Value: "sha256-abc"}})
+	assetData := &asset.Asset{
+		Digest:      &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+		ExpireAt:    timestamppb.New(time.Unix(2000, 0)),
+		LastUpdated: timestamppb.New(time.Unix(1000, 500)),
+		OriginUri:   "https://mirror.example.com/a.txt",
+	}
+
+	assetStore := newTestSQLiteAssetStore(ctx, t, mock.NewMockClock(ctrl))
+
+	_, err := assetStore.Get(ctx, assetRef, instanceName)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+
+	require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+	storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, assetData, storedAsset)
+
+	// Assets should be stored separately per instance name.
+	_, err = assetStore.Get(ctx, assetRef, digest.EmptyInstanceName)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+
+	// Overwriting an asset should replace all of its properties.
+	// Timestamps that are unset should remain unset.
+	updatedAssetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "aec070645fe53ee3b3763059376134f058cc337247c978add178b6ccdfb0019f", SizeBytes: 222},
+	}
+	require.NoError(t, assetStore.Put(ctx, assetRef, updatedAssetData, instanceName))
+	storedAsset, err = assetStore.Get(ctx, assetRef, instanceName)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, updatedAssetData, storedAsset)
+
+	require.NoError(t, assetStore.Delete(ctx, assetRef, instanceName))
+	_, err = assetStore.Get(ctx, assetRef, instanceName)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+}
+
+func TestSQLiteAssetStoreSecretQualifiers(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	defer db.Close()
+	assetStore, err := storage.NewSQLiteAssetStore(ctx, db, mock.NewMockClock(ctrl))
+	require.NoError(t, err)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	secret := `{"https://example.com/a.txt":{"Authorization":["Bearer hunter2"]}}`
+	assetRef := storage.NewAssetReference(
+		[]string{"https://example.com/a.txt"},
+		[]*remoteasset.Qualifier{
+			{Name: "bazel.auth_headers", Value: secret},
+			{Name: "checksum.sri", Value: "sha256-abc"},
+		})
+	assetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+	}
+	require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+
+	// The asset should be obtainable using the original credentials,
+	// but not using different ones.
+	storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+	require.NoError(t, err)
+	testutil.RequireEqualProto(t, assetData, storedAsset)
+	_, err = assetStore.Get(ctx, storage.NewAssetReference(
+		[]string{"https://example.com/a.txt"},
+		[]*remoteasset.Qualifier{
+			{Name: "bazel.auth_headers", Value: `{"https://example.com/a.txt":{"Authorization":["Bearer other"]}}`},
+			{Name: "checksum.sri", Value: "sha256-abc"},
+		}), instanceName)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+
+	// The credentials should not be written to the database.
+	var count int
+	require.NoError(t, db.QueryRowContext(
+		ctx,
+		"SELECT COUNT(*) FROM assets WHERE instr(qualifiers, 'hunter2') > 0 OR instr(CAST(reference AS TEXT), 'hunter2') > 0",
+	).Scan(&count))
+	require.Equal(t, 0, count)
+
+	// Nor should they be returned when listing assets.
+	assets, _, err := assetStore.List(ctx, instanceName, "", "", 10)
+	require.NoError(t, err)
+	require.Len(t, assets, 1)
+	require.Len(t, assets[0].Reference.Qualifiers, 2)
+	require.Equal(t, "bazel.auth_headers", assets[0].Reference.Qualifiers[0].Name)
+	require.NotContains(t, assets[0].Reference.Qualifiers[0].Value, "hunter2")
+	testutil.RequireEqualProto(t, &remoteasset.Qualifier{Name: "checksum.sri", Value: "sha256-abc"}, assets[0].Reference.Qualifiers[1])
+}
+
+func TestSQLiteAssetStoreList(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	blobDigest := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	ref1 := storage.NewAssetReference([]string{"https://example.com/1.txt"}, nil)
+	ref2 := storage.NewAssetReference([]string{"https://example.com/2.txt", "https://mirror.example.com/2.txt"}, nil)
+	ref3 := storage.NewAssetReference([]string{"https://mirror.example.com/3.txt"}, nil)
+	ref4 := storage.NewAssetReference([]string{"https://example.com/4.txt"}, nil)
+
+	assetStore := newTestSQLiteAssetStore(ctx, t, mock.NewMockClock(ctrl))
+	for _, ref := range []*asset.AssetReference{ref1, ref2, ref3} {
+		require.NoError(t, assetStore.Put(ctx, ref, &asset.Asset{Digest: blobDigest}, instanceName))
+	}
+	require.NoError(t, assetStore.Put(ctx, ref4, &asset.Asset{Digest: blobDigest}, digest.EmptyInstanceName))
+
+	getReferences := func(assets []storage.EnumeratedAsset) []*asset.AssetReference {
+		var refs []*asset.AssetReference
+		for _, a := range assets {
+			testutil.RequireEqualProto(t, &asset.Asset{Digest: blobDigest}, a.Asset)
+			refs = append(refs, a.Reference)
+		}
+		return refs
+	}
+
+	t.Run("Paginated", func(t *testing.T) {
+		assets, pageToken, err := assetStore.List(ctx, instanceName, "", "", 2)
+		require.NoError(t, err)
+		require.NotEmpty(t, pageToken)
+		refs := getReferences(assets)
+		require.Len(t, refs, 2)
+		testutil.RequireEqualProto(t, ref1, refs[0])
+		testutil.RequireEqualProto(t, ref2, refs[1])
+
+		assets, pageToken, err = assetStore.List(ctx, instanceName, "", pageToken, 2)
+		require.NoError(t, err)
+		require.Empty(t, pageToken)
+		refs = getReferences(assets)
+		require.Len(t, refs, 1)
+		testutil.RequireEqualProto(t, ref3, refs[0])
+	})
+
+	t.Run("Prefix", func(t *testing.T) {
+		assets, pageToken, err := assetStore.List(ctx, instanceName, "https://mirror.", "", 10)
+		require.NoError(t, err)
+		require.Empty(t, pageToken)
+		refs := getReferences(assets)
+		require.Len(t, refs, 2)
+		testutil.RequireEqualProto(t, ref2, refs[0])
+		testutil.RequireEqualProto(t, ref3, refs[1])
+	})
+
+	t.Run("InvalidPageToken", func(t *testing.T) {
+		_, _, err := assetStore.List(ctx, instanceName, "", "hello", 10)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestSQLiteAssetStoreExpiration(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	blobDigest1 := &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111}
+	blobDigest2 := &remoteexecution.Digest{Hash: "aec070645fe53ee3b3763059376134f058cc337247c978add178b6ccdfb0019f", SizeBytes: 222}
+	expiredRef := storage.NewAssetReference([]string{"https://example.com/expired.txt"}, nil)
+	validRef := storage.NewAssetReference([]string{"https://example.com/valid.txt"}, nil)
+	permanentRef := storage.NewAssetReference([]string{"https://example.com/permanent.txt"}, nil)
+
+	clock := mock.NewMockClock(ctrl)
+	assetStore := newTestSQLiteAssetStore(ctx, t, clock)
+	require.NoError(t, assetStore.Put(ctx, expiredRef, &asset.Asset{
+		Digest:      blobDigest1,
+		ExpireAt:    timestamppb.New(time.Unix(999, 0)),
+		LastUpdated: timestamppb.New(time.Unix(500, 0)),
+	}, instanceName))
+	require.NoError(t, assetStore.Put(ctx, validRef, &asset.Asset{
+		Digest:      blobDigest1,
+		ExpireAt:    timestamppb.New(time.Unix(1001, 0)),
+		LastUpdated: timestamppb.New(time.Unix(600, 0)),
+	}, instanceName))
+	require.NoError(t, assetStore.Put(ctx, permanentRef, &asset.Asset{
+		Digest:      blobDigest2,
+		ExpireAt:    timestamppb.New(time.Unix(0, 0)),
+		LastUpdated: timestamppb.New(time.Unix(700, 0)),
+	}, instanceName))
+
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	statistics, err := assetStore.GetStatistics(ctx, instanceName)
+	require.NoError(t, err)
+	require.Equal(t, &storage.AssetStoreStatistics{
+		Assets:            3,
+		ExpiredAssets:     1,
+		DistinctDigests:   2,
+		OldestLastUpdated: time.Unix(500, 0),
+		NewestLastUpdated: time.Unix(700, 0),
+	}, statistics)
+
+	deleted, err := assetStore.DeleteExpired(ctx, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	_, err = assetStore.Get(ctx, expiredRef, instanceName)
+	testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found"), err)
+	_, err = assetStore.Get(ctx, validRef, instanceName)
+	require.NoError(t, err)
+	_, err = assetStore.Get(ctx, permanentRef, instanceName)
+	require.NoError(t, err)
+
+	// Statistics for instance names without assets.
+	clock.EXPECT().Now().Return(time.Unix(1000, 0))
+	statistics, err = assetStore.GetStatistics(ctx, digest.EmptyInstanceName)
+	require.NoError(t, err)
+	require.Equal(t, &storage.AssetStoreStatistics{}, statistics)
+}
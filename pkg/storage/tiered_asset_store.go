@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"log"
+
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type tieredAssetStore struct {
+	tiers []AssetStore
+}
+
+// NewTieredAssetStore creates an AssetStore that consists of multiple
+// tiers, ordered from fastest to slowest. Get() consults the tiers in
+// order, and copies assets found in a slower tier into all faster
+// tiers, so that subsequent calls are served by the fastest tier.
+// Tiers that fail are skipped. Their error is only returned if none of
+// the other tiers contain the asset.
+// Put() and Delete() are applied to all tiers.
+func NewTieredAssetStore(tiers []AssetStore) AssetStore {
+	return &tieredAssetStore{
+		tiers: tiers,
+	}
+}
+
+func (as *tieredAssetStore) Get(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) (*asset.Asset, error) {
+	var tierErr error
+	for i, tier := range as.tiers {
+		assetData, err := tier.Get(ctx, ref, instance)
+		if err == nil {
+			// Backfill the faster tiers. Failing to do so
+			// only affects performance, so don't let it
+			// cause the request to fail.
+			for j := 0; j < i; j++ {
+				if err := as.tiers[j].Put(ctx, ref, assetData, instance); err != nil {
+					log.Printf("Failed to backfill asset %s into tier %d: %v", ref.Uris, j, err)
+				}
+			}
+			return assetData, nil
+		}
+		if status.Code(err) != codes.NotFound {
+			// A failing tier shouldn't prevent the asset
+			// from being obtained from the other tiers.
+			err = util.StatusWrapf(err, "Tier %d", i)
+			log.Printf("Failed to get asset %s: %v", ref.Uris, err)
+			if tierErr == nil {
+				tierErr = err
+			}
+		}
+	}
+	if tierErr != nil {
+		// The asset may be present in a tier that failed.
+		return nil, tierErr
+	}
+	return nil, status.Error(codes.NotFound, "Asset not found in any tier")
+}
+
+func (as *tieredAssetStore) Put(ctx context.Context, ref *asset.AssetReference, data *asset.Asset, instance digest.InstanceName) error {
+	// Write to the slowest tier first, so that the asset is never
+	// present in a faster tier without being present in the slower
+	// ones.
+	for i := len(as.tiers) - 1; i >= 0; i-- {
+		if err := as.tiers[i].Put(ctx, ref, data, instance); err != nil {
+			return util.StatusWrapf(err, "Tier %d", i)
+		}
+	}
+	return nil
+}
+
+func (as *tieredAssetStore) Delete(ctx context.Context, ref *asset.AssetReference, instance digest.InstanceName) error {
+	// Delete from the slowest tier first, so that concurrent calls
+	// to Get() can't backfill the faster tiers with the asset after
+	// it has been deleted from them.
+	for i := len(as.tiers) - 1; i >= 0; i-- {
+		if err := as.tiers[i].Delete(ctx, ref, instance); err != nil {
+			return util.StatusWrapf(err, "Tier %d", i)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/buildbarn/bb-storage/pkg/testutil"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTieredAssetStoreGet(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"}, nil)
+	assetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+	}
+
+	tier0 := mock.NewMockAssetStore(ctrl)
+	tier1 := mock.NewMockAssetStore(ctrl)
+	tier2 := mock.NewMockAssetStore(ctrl)
+	assetStore := storage.NewTieredAssetStore([]storage.AssetStore{tier0, tier1, tier2})
+
+	t.Run("FastestTier", func(t *testing.T) {
+		tier0.EXPECT().Get(ctx, assetRef, instanceName).Return(assetData, nil)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, assetData, storedAsset)
+	})
+
+	t.Run("Backfill", func(t *testing.T) {
+		// Assets found in a slower tier should be copied into
+		// the faster tiers. Failures to do so are not fatal.
+		tier0.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier1.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier2.EXPECT().Get(ctx, assetRef, instanceName).Return(assetData, nil)
+		tier0.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+		tier1.EXPECT().Put(ctx, assetRef, assetData, instanceName).Return(status.Error(codes.Unavailable, "Server offline"))
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, assetData, storedAsset)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		tier0.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier1.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier2.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+
+		_, err := assetStore.Get(ctx, assetRef, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.NotFound, "Asset not found in any tier"), err)
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		tier0.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier1.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.Unavailable, "Server offline"))
+		tier2.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+
+		_, err := assetStore.Get(ctx, assetRef, instanceName)
+		testutil.RequireEqualStatus(t, status.Error(codes.Unavailable, "Tier 1: Server offline"), err)
+	})
+
+	t.Run("FailureSkipped", func(t *testing.T) {
+		// A failing tier should not prevent the asset from
+		// being returned by a slower tier.
+		tier0.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.Unavailable, "Server offline"))
+		tier1.EXPECT().Get(ctx, assetRef, instanceName).Return(nil, status.Error(codes.NotFound, "Asset not found"))
+		tier2.EXPECT().Get(ctx, assetRef, instanceName).Return(assetData, nil)
+		tier0.EXPECT().Put(ctx, assetRef, assetData, instanceName).Return(status.Error(codes.Unavailable, "Server offline"))
+		tier1.EXPECT().Put(ctx, assetRef, assetData, instanceName)
+
+		storedAsset, err := assetStore.Get(ctx, assetRef, instanceName)
+		require.NoError(t, err)
+		testutil.RequireEqualProto(t, assetData, storedAsset)
+	})
+}
+
+func TestTieredAssetStorePutDelete(t *testing.T) {
+	ctrl, ctx := gomock.WithContext(context.Background(), t)
+
+	instanceName := digest.MustNewInstanceName("foo")
+	assetRef := storage.NewAssetReference([]string{"https://example.com/example.txt"}, nil)
+	assetData := &asset.Asset{
+		Digest: &remoteexecution.Digest{Hash: "58de0f27ce0f781e5c109f18b0ee6905bdf64f2b1009e225ac67a27f656a0643", SizeBytes: 111},
+	}
+
+	tier0 := mock.NewMockAssetStore(ctrl)
+	tier1 := mock.NewMockAssetStore(ctrl)
+	assetStore := storage.NewTieredAssetStore([]storage.AssetStore{tier0, tier1})
+
+	t.Run("Put", func(t *testing.T) {
+		// The slowest tier should be written first.
+		gomock.InOrder(
+			tier1.EXPECT().Put(ctx, assetRef, assetData, instanceName),
+			tier0.EXPECT().Put(ctx, assetRef, assetData, instanceName))
+
+		require.NoError(t, assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("PutFailure", func(t *testing.T) {
+		tier1.EXPECT().Put(ctx, assetRef, assetData, instanceName).Return(status.Error(codes.Unavailable, "Server offline"))
+
+		testutil.RequireEqualStatus(
+			t,
+			status.Error(codes.Unavailable, "Tier 1: Server offline"),
+			assetStore.Put(ctx, assetRef, assetData, instanceName))
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		gomock.InOrder(
+			tier1.EXPECT().Delete(ctx, assetRef, instanceName),
+			tier0.EXPECT().Delete(ctx, assetRef, instanceName))
+
+		require.NoError(t, assetStore.Delete(ctx, assetRef, instanceName))
+	})
+}
@@ -0,0 +1,295 @@
+package webui
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/qualifier"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-storage/pkg/auth"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	bb_http "github.com/buildbarn/bb-storage/pkg/http"
+	"github.com/buildbarn/bb-storage/pkg/util"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+//go:embed templates/*.html
+var templatesFS embed.FS
+
+var templates = template.Must(template.New("templates").Funcs(template.FuncMap{
+	"formatTime": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+	"formatDigest": func(d *remoteexecution.Digest) string {
+		return fmt.Sprintf("%s-%d", d.GetHash(), d.GetSizeBytes())
+	},
+}).ParseFS(templatesFS, "templates/*.html"))
+
+type assetBrowser struct {
+	assetStore   storage.AssetStore
+	fetchHistory *fetch.FetchHistory
+	authorizer   auth.Authorizer
+	browserURL   *url.URL
+}
+
+// NewAssetBrowserHandler creates an HTTP handler that serves web pages
+// for looking up assets in the asset cache, and for displaying the
+// most recent fetches performed by the server.
+//
+// The asset cache is optional. The fetch history is filtered using the
+// provided Authorizer, so that users can only see fetches for instance
+// names they are permitted to fetch from. If browserURL is not nil,
+// digests are linked to the corresponding pages of bb-browser.
+func NewAssetBrowserHandler(assetStore storage.AssetStore, fetchHistory *fetch.FetchHistory, authorizer auth.Authorizer, browserURL *url.URL) http.Handler {
+	ab := &assetBrowser{
+		assetStore:   assetStore,
+		fetchHistory: fetchHistory,
+		authorizer:   authorizer,
+		browserURL:   browserURL,
+	}
+	router := http.NewServeMux()
+	router.HandleFunc("/", ab.handleIndex)
+	router.HandleFunc("/asset", ab.handleAsset)
+	return router
+}
+
+// browserLink is a hyperlink to a page of bb-browser.
+type browserLink struct {
+	Title string
+	URL   string
+}
+
+// getBrowserLinks returns links to the pages of bb-browser that
+// display the object referenced by an asset. As assets don't record
+// whether they refer to a file or directory, a link is provided for
+// every type that is permitted by the operation.
+func (ab *assetBrowser) getBrowserLinks(instanceName bb_digest.InstanceName, blobDigest *remoteexecution.Digest, uri string, blob, directory bool) []browserLink {
+	if ab.browserURL == nil || blobDigest == nil {
+		return nil
+	}
+	digestFunction, err := instanceName.GetDigestFunction(remoteexecution.DigestFunction_UNKNOWN, len(blobDigest.Hash))
+	if err != nil {
+		return nil
+	}
+	d, err := digestFunction.NewDigestFromProto(blobDigest)
+	if err != nil {
+		return nil
+	}
+	digestPath := path.Join(
+		d.GetInstanceName().String(),
+		"blobs",
+		strings.ToLower(d.GetDigestFunction().GetEnumValue().String()))
+	digestName := fmt.Sprintf("%s-%d", d.GetHashString(), d.GetSizeBytes())
+
+	var links []browserLink
+	if blob {
+		// bb-browser requires a filename to be provided, which is
+		// used when downloading the file.
+		filename := "file"
+		if parsedURI, err := url.Parse(uri); err == nil {
+			if base := path.Base(parsedURI.Path); base != "." && base != "/" {
+				filename = base
+			}
+		}
+		if u, err := ab.browserURL.Parse(path.Join(digestPath, "file", digestName, filename)); err == nil {
+			links = append(links, browserLink{Title: "Browse file", URL: u.String()})
+		}
+	}
+	if directory {
+		if u, err := ab.browserURL.Parse(path.Join(digestPath, "directory", digestName) + "/"); err == nil {
+			links = append(links, browserLink{Title: "Browse directory", URL: u.String()})
+		}
+	}
+	return links
+}
+
+// getAssetURL returns the URL of the page that displays an asset. No
+// URL is returned for assets having qualifiers that may contain
+// credentials. These can't be placed in URLs, as URLs end up in browser
+// histories and access logs, while omitting them would cause a
+// different asset to be looked up.
+func getAssetURL(instanceName bb_digest.InstanceName, uris []string, qualifiers []*remoteasset.Qualifier) string {
+	qualifierStrings := make([]string, 0, len(qualifiers))
+	for _, q := range qualifiers {
+		if qualifier.SecretQualifiers.Contains(q.Name) {
+			return ""
+		}
+		qualifierStrings = append(qualifierStrings, q.Name+"="+q.Value)
+	}
+	return "asset?" + url.Values{
+		"instance_name": {instanceName.String()},
+		"uris":          {strings.Join(uris, "\n")},
+		"qualifiers":    {strings.Join(qualifierStrings, "\n")},
+	}.Encode()
+}
+
+// historyEntry is a FetchHistoryEntry, annotated with information
+// needed to render it.
+type historyEntry struct {
+	fetch.FetchHistoryEntry
+	AssetURL     string
+	BrowserLinks []browserLink
+}
+
+func (ab *assetBrowser) getAuthorizedHistory(r *http.Request, entries []fetch.FetchHistoryEntry) []historyEntry {
+	// Only display fetches for instance names the user is permitted
+	// to fetch from.
+	var instanceNames []bb_digest.InstanceName
+	instanceNameIndices := map[bb_digest.InstanceName]int{}
+	for _, entry := range entries {
+		if _, ok := instanceNameIndices[entry.InstanceName]; !ok {
+			instanceNameIndices[entry.InstanceName] = len(instanceNames)
+			instanceNames = append(instanceNames, entry.InstanceName)
+		}
+	}
+	authorizationErrors := ab.authorizer.Authorize(r.Context(), instanceNames)
+
+	authorizedEntries := make([]historyEntry, 0, len(entries))
+	for _, entry := range entries {
+		if authorizationErrors[instanceNameIndices[entry.InstanceName]] != nil {
+			continue
+		}
+		authorizedEntries = append(authorizedEntries, historyEntry{
+			FetchHistoryEntry: entry,
+			AssetURL:          getAssetURL(entry.InstanceName, entry.URIs, entry.Qualifiers),
+			BrowserLinks:      ab.getBrowserLinks(entry.InstanceName, entry.Digest, entry.URI, entry.Operation == "FetchBlob", entry.Operation == "FetchDirectory"),
+		})
+	}
+	return authorizedEntries
+}
+
+func (ab *assetBrowser) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	ab.renderTemplate(w, http.StatusOK, "index.html", struct {
+		HasAssetCache bool
+		Fetches       []historyEntry
+		Errors        []historyEntry
+	}{
+		HasAssetCache: ab.assetStore != nil,
+		Fetches:       ab.getAuthorizedHistory(r, ab.fetchHistory.GetFetches()),
+		Errors:        ab.getAuthorizedHistory(r, ab.fetchHistory.GetErrors()),
+	})
+}
+
+// splitLines splits the value of a text area into its non-empty lines.
+func splitLines(s string) []string {
+	var lines []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// assetLookup is the result of looking up a single asset reference in
+// the asset cache.
+type assetLookup struct {
+	URIs         []string
+	Asset        *asset.Asset
+	ExpireAt     *time.Time
+	LastUpdated  *time.Time
+	Status       *status.Status
+	BrowserLinks []browserLink
+}
+
+func (ab *assetBrowser) handleAsset(w http.ResponseWriter, r *http.Request) {
+	if ab.assetStore == nil {
+		http.Error(w, "This server is not configured with an asset cache", http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	instanceName, err := bb_digest.NewInstanceName(query.Get("instance_name"))
+	if err != nil {
+		ab.renderError(w, util.StatusWrapf(err, "Invalid instance name %#v", query.Get("instance_name")))
+		return
+	}
+	uris := splitLines(query.Get("uris"))
+	if len(uris) == 0 {
+		ab.renderError(w, status.Error(codes.InvalidArgument, "At least one URI must be provided"))
+		return
+	}
+	var qualifiers []*remoteasset.Qualifier
+	for _, line := range splitLines(query.Get("qualifiers")) {
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			ab.renderError(w, status.Errorf(codes.InvalidArgument, "Qualifier %#v is not of the form name=value", line))
+			return
+		}
+		qualifiers = append(qualifiers, &remoteasset.Qualifier{Name: name, Value: value})
+	}
+
+	// Fetch and Push store assets both under the full list of URIs
+	// and under every URI individually. Display all of them.
+	referenceURIs := [][]string{uris}
+	if len(uris) > 1 {
+		for _, uri := range uris {
+			referenceURIs = append(referenceURIs, []string{uri})
+		}
+	}
+	lookups := make([]assetLookup, 0, len(referenceURIs))
+	for _, uris := range referenceURIs {
+		lookup := assetLookup{URIs: uris}
+		assetData, err := ab.assetStore.Get(r.Context(), storage.NewAssetReference(uris, qualifiers), instanceName)
+		if err != nil {
+			lookup.Status = status.Convert(err)
+		} else {
+			lookup.Asset = assetData
+			// Assets without an expiration time, or with an
+			// expiration time of the epoch, don't expire.
+			if expireAt := assetData.ExpireAt; expireAt != nil && !expireAt.AsTime().Equal(time.Unix(0, 0)) {
+				t := expireAt.AsTime()
+				lookup.ExpireAt = &t
+			}
+			if assetData.LastUpdated != nil {
+				t := assetData.LastUpdated.AsTime()
+				lookup.LastUpdated = &t
+			}
+			lookup.BrowserLinks = ab.getBrowserLinks(instanceName, assetData.Digest, uris[0], true, true)
+		}
+		lookups = append(lookups, lookup)
+	}
+
+	ab.renderTemplate(w, http.StatusOK, "asset.html", struct {
+		InstanceName bb_digest.InstanceName
+		Qualifiers   []*remoteasset.Qualifier
+		Lookups      []assetLookup
+	}{
+		InstanceName: instanceName,
+		Qualifiers:   qualifiers,
+		Lookups:      lookups,
+	})
+}
+
+func (ab *assetBrowser) renderError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	ab.renderTemplate(w, bb_http.StatusCodeFromGRPCCode(s.Code()), "error.html", s)
+}
+
+func (ab *assetBrowser) renderTemplate(w http.ResponseWriter, statusCode int, name string, data interface{}) {
+	var b bytes.Buffer
+	if err := templates.ExecuteTemplate(&b, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(statusCode)
+	w.Write(b.Bytes())
+}
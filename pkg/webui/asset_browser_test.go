@@ -0,0 +1,125 @@
+package webui_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	remoteasset "github.com/bazelbuild/remote-apis/build/bazel/remote/asset/v1"
+	remoteexecution "github.com/bazelbuild/remote-apis/build/bazel/remote/execution/v2"
+	"github.com/buildbarn/bb-remote-asset/internal/mock"
+	"github.com/buildbarn/bb-remote-asset/pkg/fetch"
+	"github.com/buildbarn/bb-remote-asset/pkg/proto/asset"
+	"github.com/buildbarn/bb-remote-asset/pkg/storage"
+	"github.com/buildbarn/bb-remote-asset/pkg/webui"
+	bb_digest "github.com/buildbarn/bb-storage/pkg/digest"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestAssetBrowserAsset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	instanceName := bb_digest.MustNewInstanceName("instance")
+	uri := "https://example.com/example.tar.gz"
+	qualifiers := []*remoteasset.Qualifier{{Name: "resource_type", Value: "application/x-tar"}}
+	blobDigest := &remoteexecution.Digest{Hash: "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db", SizeBytes: 123}
+
+	assetStore := mock.NewMockAssetStore(ctrl)
+	browserURL, err := url.Parse("https://browser.example.com/")
+	require.NoError(t, err)
+	handler := webui.NewAssetBrowserHandler(assetStore, fetch.NewFetchHistory(10), mock.NewMockAuthorizer(ctrl), browserURL)
+
+	t.Run("Found", func(t *testing.T) {
+		assetStore.EXPECT().Get(gomock.Any(), storage.NewAssetReference([]string{uri}, qualifiers), instanceName).Return(&asset.Asset{
+			Digest:      blobDigest,
+			LastUpdated: timestamppb.New(time.Unix(1700000000, 0)),
+		}, nil)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/asset?instance_name=instance&uris="+url.QueryEscape(uri)+"&qualifiers=resource_type%3Dapplication%2Fx-tar", nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		body := recorder.Body.String()
+		require.Contains(t, body, "d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db-123")
+		require.Contains(t, body, "2023-11-14T22:13:20Z")
+		require.Contains(t, body, "https://browser.example.com/instance/blobs/sha256/file/d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db-123/example.tar.gz")
+		require.Contains(t, body, "https://browser.example.com/instance/blobs/sha256/directory/d0d829c4c0ce64787cb1c998a9c29a109f8ed005633132fda4f29982487b04db-123/")
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		assetStore.EXPECT().Get(gomock.Any(), storage.NewAssetReference([]string{uri}, nil), instanceName).Return(nil, status.Error(codes.NotFound, "Object not found"))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/asset?instance_name=instance&uris="+url.QueryEscape(uri), nil))
+		require.Equal(t, http.StatusOK, recorder.Code)
+		require.Contains(t, recorder.Body.String(), "NotFound: Object not found")
+	})
+
+	t.Run("MalformedQualifier", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/asset?instance_name=instance&uris="+url.QueryEscape(uri)+"&qualifiers=foo", nil))
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+}
+
+func TestAssetBrowserIndex(t *testing.T) {
+	ctrl := gomock.NewController(t)
+
+	allowedInstanceName := bb_digest.MustNewInstanceName("allowed")
+	deniedInstanceName := bb_digest.MustNewInstanceName("denied")
+
+	fetchHistory := fetch.NewFetchHistory(10)
+	fetchHistory.Add(fetch.FetchHistoryEntry{
+		Operation:    "FetchBlob",
+		InstanceName: allowedInstanceName,
+		URIs:         []string{"https://example.com/public.txt"},
+		Qualifiers: []*remoteasset.Qualifier{
+			{Name: "resource_type", Value: "text/plain"},
+		},
+		Status: status.New(codes.NotFound, "Server returned 404 Not Found"),
+	})
+	fetchHistory.Add(fetch.FetchHistoryEntry{
+		Operation:    "FetchBlob",
+		InstanceName: allowedInstanceName,
+		URIs:         []string{"https://example.com/allowed.txt"},
+		Qualifiers: []*remoteasset.Qualifier{
+			{Name: "bazel.auth_headers", Value: "[redacted]"},
+			{Name: "resource_type", Value: "text/plain"},
+		},
+		Status: status.New(codes.NotFound, "Server returned 404 Not Found"),
+	})
+	fetchHistory.Add(fetch.FetchHistoryEntry{
+		Operation:    "FetchBlob",
+		InstanceName: deniedInstanceName,
+		URIs:         []string{"https://example.com/denied.txt"},
+		Status:       status.New(codes.NotFound, "Server returned 404 Not Found"),
+	})
+
+	// Only fetches for instance names the user is permitted to
+	// fetch from should be displayed.
+	authorizer := mock.NewMockAuthorizer(ctrl)
+	authorizer.EXPECT().Authorize(gomock.Any(), []bb_digest.InstanceName{deniedInstanceName, allowedInstanceName}).
+		Return([]error{status.Error(codes.PermissionDenied, "Not authorized"), nil}).
+		Times(2)
+	handler := webui.NewAssetBrowserHandler(nil, fetchHistory, authorizer, nil)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	body := recorder.Body.String()
+	require.Contains(t, body, "https://example.com/allowed.txt")
+	require.Contains(t, body, "NotFound: Server returned 404 Not Found")
+	require.NotContains(t, body, "https://example.com/denied.txt")
+
+	// Assets fetched with credentials should not be linked, as the
+	// link can't contain the credentials needed to look them up.
+	require.Contains(t, body, "uris=https%3A%2F%2Fexample.com%2Fpublic.txt")
+	require.Contains(t, body, "resource_type%3Dtext%2Fplain")
+	require.NotContains(t, body, "uris=https%3A%2F%2Fexample.com%2Fallowed.txt")
+	require.NotContains(t, body, "bazel.auth_headers%3D")
+}